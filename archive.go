@@ -0,0 +1,211 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// WithArchiveView returns a view onto the same git tree that behaves like
+// “git archive”:
+//   - files and directories with the “export-ignore” git attribute are hidden;
+//     they cannot be opened, and they don't show up in directory listings.
+//   - files with the “export-subst” git attribute get their “$Format:...$”
+//     placeholders expanded, using the commit this file system was created
+//     from. If there is no commit, such as when using [New], the placeholders
+//     are left untouched.
+//
+// Combine with [FS.WithTextConversion] to additionally get the line ending
+// conversions applied, as “git archive” also does.
+func (gfs *FS) WithArchiveView() *FS {
+	view := *gfs
+	view.archive = true
+	return &view
+}
+
+// exportIgnored returns true if the named file or directory or any of its
+// parent directories has the “export-ignore” attribute set and thus is
+// excluded from archive views.
+func (gfs *FS) exportIgnored(name string) bool {
+	if !gfs.archive {
+		return false
+	}
+	for i := 0; i <= len(name); i++ {
+		if i < len(name) && name[i] != '/' {
+			continue
+		}
+		attr, ok := gfs.attributesFor(name[:i])["export-ignore"]
+		if ok && attr.IsSet() {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedEntries returns the entries of the specified directory tree that
+// aren't “export-ignore”d. The directory itself must not be export-ignored.
+func (gfs *FS) exportedEntries(dir string, tree *object.Tree) []object.TreeEntry {
+	stack := gfs.attributes(dir)
+	entries := make([]object.TreeEntry, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		attr, ok := stack.match(path.Join(dir, entry.Name))["export-ignore"]
+		if ok && attr.IsSet() {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// expandFormat expands all “$Format:...$” placeholders in the specified
+// contents with information from the specified commit, in the same way as
+// “git archive” does for files with the “export-subst” attribute.
+func expandFormat(contents []byte, commit *object.Commit) []byte {
+	const (
+		start = "$Format:"
+		end   = "$"
+	)
+	var buf bytes.Buffer
+	for {
+		i := bytes.Index(contents, []byte(start))
+		if i < 0 {
+			break
+		}
+		// as git's format_subst does, the placeholder ends at the next “$”,
+		// even if it spans multiple lines; without any further “$” the
+		// remaining contents are copied verbatim.
+		j := bytes.Index(contents[i+len(start):], []byte(end))
+		if j < 0 {
+			break
+		}
+		buf.Write(contents[:i])
+		buf.WriteString(PrettyFormat(string(contents[i+len(start):i+len(start)+j]), commit))
+		contents = contents[i+len(start)+j+len(end):]
+	}
+	buf.Write(contents)
+	return buf.Bytes()
+}
+
+// PrettyFormat returns the specified format string with the commit-related
+// placeholders expanded, following (a subset of) the placeholders of “git log
+// --pretty=format:...”:
+//   - %H, %h: commit hash, abbreviated commit hash.
+//   - %T, %t: tree hash, abbreviated tree hash.
+//   - %P, %p: parent hashes, abbreviated parent hashes.
+//   - %an, %ae, %ad, %aD, %ai, %aI, %at: author name, email, date (default,
+//     RFC2822, ISO-like, strict ISO, UNIX timestamp).
+//   - %cn, %ce, %cd, %cD, %ci, %cI, %ct: same for committer.
+//   - %s, %b, %B: subject, body, raw body.
+//   - %n, %%: newline, a literal “%”.
+//
+// Unknown placeholders are left untouched.
+func PrettyFormat(format string, commit *object.Commit) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 >= len(format) {
+			b.WriteByte(c)
+			continue
+		}
+		n, expanded := expandPlaceholder(format[i+1:], commit)
+		if n == 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(expanded)
+		i += n
+	}
+	return b.String()
+}
+
+// abbrevLen is the length of abbreviated hashes.
+const abbrevLen = 7
+
+// expandPlaceholder expands the placeholder at the beginning of the specified
+// format (after the “%”), returning the number of format characters consumed
+// and the expansion. If the placeholder is unknown, it returns zero.
+func expandPlaceholder(format string, commit *object.Commit) (int, string) {
+	switch format[0] {
+	case '%':
+		return 1, "%"
+	case 'n':
+		return 1, "\n"
+	case 'H':
+		return 1, commit.Hash.String()
+	case 'h':
+		return 1, commit.Hash.String()[:abbrevLen]
+	case 'T':
+		return 1, commit.TreeHash.String()
+	case 't':
+		return 1, commit.TreeHash.String()[:abbrevLen]
+	case 'P', 'p':
+		hashes := make([]string, 0, len(commit.ParentHashes))
+		for _, h := range commit.ParentHashes {
+			if format[0] == 'p' {
+				hashes = append(hashes, h.String()[:abbrevLen])
+				continue
+			}
+			hashes = append(hashes, h.String())
+		}
+		return 1, strings.Join(hashes, " ")
+	case 's':
+		subject, _ := splitMessage(commit.Message)
+		return 1, subject
+	case 'b':
+		_, body := splitMessage(commit.Message)
+		return 1, body
+	case 'B':
+		return 1, commit.Message
+	case 'a', 'c':
+		if len(format) < 2 {
+			return 0, ""
+		}
+		sig := commit.Author
+		if format[0] == 'c' {
+			sig = commit.Committer
+		}
+		switch format[1] {
+		case 'n':
+			return 2, sig.Name
+		case 'e':
+			return 2, sig.Email
+		case 'd':
+			return 2, sig.When.Format("Mon Jan 2 15:04:05 2006 -0700")
+		case 'D':
+			return 2, sig.When.Format("Mon, 2 Jan 2006 15:04:05 -0700")
+		case 'i':
+			return 2, sig.When.Format("2006-01-02 15:04:05 -0700")
+		case 'I':
+			return 2, sig.When.Format("2006-01-02T15:04:05-07:00")
+		case 't':
+			return 2, strconv.FormatInt(sig.When.Unix(), 10)
+		}
+	}
+	return 0, ""
+}
+
+// splitMessage splits a commit message into its subject and body. The subject
+// is the first paragraph with its lines joined by spaces.
+func splitMessage(message string) (subject string, body string) {
+	message = strings.TrimLeft(message, "\n")
+	paragraph, body, _ := strings.Cut(message, "\n\n")
+	subject = strings.Join(strings.Split(strings.TrimRight(paragraph, "\n"), "\n"), " ")
+	return subject, strings.TrimLeft(body, "\n")
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("archive view", func() {
+
+	var gfs *FS
+
+	BeforeEach(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "master"))
+	})
+
+	It("hides export-ignored files and directories", func() {
+		Expect(fs.Stat(gfs, "fodder/empty")).Error().NotTo(HaveOccurred())
+
+		afs := gfs.WithArchiveView()
+		Expect(fs.Stat(afs, "fodder")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(fs.Stat(afs, "fodder/empty")).Error().To(MatchError(fs.ErrNotExist))
+
+		entries := Successful(fs.ReadDir(afs, "."))
+		Expect(entries).NotTo(ContainElement(HaveField("Name()", "fodder")))
+		Expect(entries).To(ContainElement(HaveField("Name()", "folder")))
+
+		var names []string
+		Expect(fs.WalkDir(afs, ".", func(path string, d fs.DirEntry, err error) error {
+			names = append(names, path)
+			return err
+		})).To(Succeed())
+		Expect(names).To(ContainElement("folder/subfolder/canary.txt"))
+		Expect(names).NotTo(ContainElement(HavePrefix("fodder")))
+	})
+
+	It("substitutes placeholders in export-subst files", func() {
+		Expect(fs.ReadFile(gfs, "VERSION")).To(ContainSubstring("$Format:%H$"))
+
+		afs := gfs.WithArchiveView()
+		contents := string(Successful(fs.ReadFile(afs, "VERSION")))
+		Expect(contents).To(Equal(
+			"commit: " + gfs.commit.Hash.String() + "\n" +
+				"subject: " + strings.SplitN(gfs.commit.Message, "\n", 2)[0] + "\n" +
+				"unterminated: $Format:%H\n"))
+		Expect(Successful(fs.Stat(afs, "VERSION")).Size()).To(Equal(int64(len(contents))))
+	})
+
+	It("expands pretty format placeholders", func() {
+		when := time.Date(2023, 12, 24, 18, 0, 0, 0, time.UTC)
+		c := &object.Commit{
+			Hash:         plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"),
+			TreeHash:     plumbing.NewHash("89abcdef0123456789abcdef0123456789abcdef"),
+			ParentHashes: []plumbing.Hash{plumbing.NewHash("fedcba9876543210fedcba9876543210fedcba98")},
+			Author:       object.Signature{Name: "Brian", Email: "brian@palace.herodes", When: when},
+			Committer:    object.Signature{Name: "Pilate", Email: "pilate@palace.herodes", When: when},
+			Message:      "subject\nline\n\nbody\n",
+		}
+		Expect(PrettyFormat("%h %t %p %an <%ae> %cn %ct %aI", c)).To(Equal(
+			"0123456 89abcde fedcba9 Brian <brian@palace.herodes> Pilate 1703440800 2023-12-24T18:00:00+00:00"))
+		Expect(PrettyFormat("%s|%b|%%|%x|%", c)).To(Equal("subject line|body\n|%|%x|%"))
+	})
+
+	It("expands placeholders spanning lines", func() {
+		c := &object.Commit{
+			Hash:    plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"),
+			Message: "subject\n",
+		}
+		Expect(string(expandFormat([]byte("$Format:%h\n%s$ $Format:%H"), c))).To(Equal(
+			"0123456\nsubject $Format:%H"))
+		Expect(string(expandFormat([]byte("$Format:%h\n$Format:%s$"), c))).To(Equal(
+			"0123456\nFormat:%s$"))
+	})
+
+})
@@ -0,0 +1,147 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bufio"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+)
+
+// attributesFilename is the name of the per-directory git attributes files.
+const attributesFilename = ".gitattributes"
+
+// builtinMacros are the macro attributes that git always defines on its own,
+// without them needing to be explicitly defined in any .gitattributes file.
+var builtinMacros = []gitattributes.MatchAttribute{
+	mustParseAttributesLine("[attr]binary -diff -merge -text"),
+}
+
+func mustParseAttributesLine(line string) gitattributes.MatchAttribute {
+	m, err := gitattributes.ParseAttributesLine(line, nil, true)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// attributes is a stack of git attributes, ordered in increasing priority.
+// That is, the built-in macros first, then the top-level .gitattributes, and
+// then the .gitattributes files further down the path.
+type attributes []gitattributes.MatchAttribute
+
+// attributes returns the stack of git attributes applicable to the specified
+// directory, reading the .gitattributes files along the path from the root
+// directory down to the specified directory.
+func (gfs *FS) attributes(dir string) attributes {
+	if stack, ok := gfs.attrcache.Load(dir); ok {
+		return stack.(attributes)
+	}
+	var stack attributes
+	if dir == "." {
+		stack = append(attributes{}, builtinMacros...)
+		stack = append(stack, gfs.readAttributesFile(dir)...)
+	} else {
+		parent := gfs.attributes(path.Dir(dir))
+		stack = append(append(attributes{}, parent...), gfs.readAttributesFile(dir)...)
+	}
+	gfs.attrcache.Store(dir, stack)
+	return stack
+}
+
+// readAttributesFile returns the patterns and attributes from the .gitattributes
+// file in the specified directory, if any. Lines that cannot be parsed are
+// skipped, as git does. Macro attribute definitions are allowed only in the
+// top-level .gitattributes file.
+func (gfs *FS) readAttributesFile(dir string) []gitattributes.MatchAttribute {
+	name := attributesFilename
+	var domain []string
+	if dir != "." {
+		name = dir + "/" + attributesFilename
+		domain = strings.Split(dir, "/")
+	}
+	entry, err := gfs.tree.FindEntry(name)
+	if err != nil || entry.Mode != filemode.Regular {
+		return nil
+	}
+	blob, err := gfs.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = r.Close() }()
+	var matchattrs []gitattributes.MatchAttribute
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m, err := gitattributes.ParseAttributesLine(scanner.Text(), domain, dir == ".")
+		if err != nil || m.Name == "" {
+			continue
+		}
+		matchattrs = append(matchattrs, m)
+	}
+	return matchattrs
+}
+
+// match returns the attributes for the specified slash-separated path. In
+// contrast to [gitattributes.Matcher], the attribute values from the last
+// matching line take precedence, as it is the case with git itself.
+func (a attributes) match(name string) map[string]gitattributes.Attribute {
+	results := map[string]gitattributes.Attribute{}
+	p := strings.Split(name, "/")
+	for i := len(a) - 1; i >= 0; i-- {
+		ma := a[i]
+		if ma.Pattern == nil || !ma.Pattern.Match(p) {
+			continue
+		}
+		for j := len(ma.Attributes) - 1; j >= 0; j-- {
+			a.fill(ma.Attributes[j], results)
+		}
+	}
+	return results
+}
+
+// fill in the specified attribute unless it has already been decided upon
+// before, expanding macros as necessary.
+func (a attributes) fill(attr gitattributes.Attribute, results map[string]gitattributes.Attribute) {
+	if _, ok := results[attr.Name()]; ok {
+		return
+	}
+	results[attr.Name()] = attr
+	if !attr.IsSet() {
+		return
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		macro := a[i]
+		if macro.Pattern != nil || macro.Name != attr.Name() {
+			continue
+		}
+		for j := len(macro.Attributes) - 1; j >= 0; j-- {
+			a.fill(macro.Attributes[j], results)
+		}
+		return
+	}
+}
+
+// attributesFor returns the git attributes for the specified file or
+// directory.
+func (gfs *FS) attributesFor(name string) map[string]gitattributes.Attribute {
+	return gfs.attributes(path.Dir(name)).match(name)
+}
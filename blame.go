@@ -0,0 +1,99 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// BlameLine describes the commit that last changed a particular line of a
+// file, see [Blame].
+type BlameLine struct {
+	// Number of the line, starting with 1.
+	Number int
+	// Text of the line, without the trailing line ending.
+	Text string
+	// Commit that introduced the line in its current form.
+	Commit plumbing.Hash
+	// Author of the commit, including the author time.
+	Author object.Signature
+	// Message of the commit.
+	Message string
+}
+
+// Blame returns for each line of the named file the commit that last changed
+// this line, similar to “git blame”. Blame works on the repository backing
+// the file system, so the history is limited to what has been cloned. Lines
+// of renamed files are attributed to the commits that introduced them before
+// the rename.
+//
+// Blame always works on the file contents as stored in the repository, ignoring
+// text conversion and archive views. The name is relative to the root of the
+// file system, also for file systems returned by [FS.Sub].
+func Blame(ctx context.Context, gfs *FS, name string) ([]BlameLine, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "blame", Path: name, Err: fs.ErrInvalid}
+	}
+	if gfs.commit == nil {
+		return nil, errors.New("no commit history for file system")
+	}
+	fullname := path.Join(gfs.prefix, name)
+	entry, ok, err := entryAt(gfs.commit, fullname)
+	if err != nil || !ok {
+		return nil, &fs.PathError{Op: "blame", Path: name, Err: fs.ErrNotExist}
+	}
+	if entry.Mode == filemode.Dir || entry.Mode == filemode.Submodule {
+		return nil, &fs.PathError{Op: "blame", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := git.Blame(gfs.commit, fullname)
+	if err != nil {
+		return nil, fmt.Errorf("cannot blame %q, reason: %w", name, err)
+	}
+	commits := map[plumbing.Hash]*object.Commit{}
+	lines := make([]BlameLine, 0, len(result.Lines))
+	for idx, line := range result.Lines {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		commit, ok := commits[line.Hash]
+		if !ok {
+			commit, err = gfs.repo.CommitObject(line.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("cannot blame %q, reason: %w", name, err)
+			}
+			commits[line.Hash] = commit
+		}
+		lines = append(lines, BlameLine{
+			Number:  idx + 1,
+			Text:    line.Text,
+			Commit:  line.Hash,
+			Author:  commit.Author,
+			Message: commit.Message,
+		})
+	}
+	return lines, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+	"time"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("blaming", Ordered, func() {
+
+	var gfs *FS
+
+	BeforeAll(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, localremote.CreateTransientHistoryRepo(), "HEAD"))
+	})
+
+	It("blames lines, following renames", func(ctx context.Context) {
+		lines := Successful(Blame(ctx, gfs, "docs/notes.md"))
+		Expect(lines).To(HaveExactElements(
+			And(HaveField("Number", 1), HaveField("Text", "alpha"), HaveField("Message", "adds notes")),
+			And(HaveField("Number", 2), HaveField("Text", "BETA"), HaveField("Message", "emphasizes beta")),
+			And(HaveField("Number", 3), HaveField("Text", "gamma"), HaveField("Message", "adds notes")),
+			And(HaveField("Number", 4), HaveField("Text", "delta"), HaveField("Message", "adds delta")),
+		))
+		Expect(lines[1].Author.Name).To(Equal("Bob"))
+		Expect(lines[1].Author.When).To(BeTemporally("==", localremote.HistoryEpoch.Add(time.Hour)))
+		Expect(lines[3].Commit).To(Equal(gfs.CommitHash()))
+		Expect(lines[0].Commit).To(Equal(lines[2].Commit))
+	})
+
+	It("blames lines inside subtrees", func(ctx context.Context) {
+		subfs := Successful(gfs.sub("docs"))
+		Expect(Blame(ctx, subfs, "notes.md")).To(HaveLen(4))
+	})
+
+	It("reports errors", func(ctx context.Context) {
+		Expect(Blame(ctx, gfs, "/other.txt")).Error().To(MatchError(fs.ErrInvalid))
+		Expect(Blame(ctx, gfs, "notes.txt")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(Blame(ctx, gfs, "docs")).Error().To(MatchError(fs.ErrInvalid))
+		Expect(Blame(ctx, New(gfs.repo, gfs.tree, gfs.mtime), "other.txt")).Error().To(
+			MatchError("no commit history for file system"))
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(Blame(ctx, gfs, "other.txt")).Error().To(MatchError(context.Canceled))
+	})
+
+})
@@ -0,0 +1,117 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Commit returns the commit this file system was created from, or nil if
+// there is no commit, such as when using [New].
+func (gfs *FS) Commit() *object.Commit { return gfs.commit }
+
+// CommitHash returns the hash of the commit this file system was created
+// from, or the zero hash if there is no commit.
+func (gfs *FS) CommitHash() plumbing.Hash {
+	if gfs.commit == nil {
+		return plumbing.ZeroHash
+	}
+	return gfs.commit.Hash
+}
+
+// Author returns the author of the commit this file system was created from,
+// or a zero signature if there is no commit.
+func (gfs *FS) Author() object.Signature {
+	if gfs.commit == nil {
+		return object.Signature{}
+	}
+	return gfs.commit.Author
+}
+
+// Committer returns the committer of the commit this file system was created
+// from, or a zero signature if there is no commit.
+func (gfs *FS) Committer() object.Signature {
+	if gfs.commit == nil {
+		return object.Signature{}
+	}
+	return gfs.commit.Committer
+}
+
+// Message returns the message of the commit this file system was created
+// from, or an empty string if there is no commit.
+func (gfs *FS) Message() string {
+	if gfs.commit == nil {
+		return ""
+	}
+	return gfs.commit.Message
+}
+
+// Parents returns the hashes of the parent commits of the commit this file
+// system was created from, or nil if there is no commit.
+func (gfs *FS) Parents() []plumbing.Hash {
+	if gfs.commit == nil {
+		return nil
+	}
+	return append([]plumbing.Hash(nil), gfs.commit.ParentHashes...)
+}
+
+// Revision returns the revision this file system was opened from. For
+// revisions naming a tag or branch this is the fully qualified reference name
+// in the remote repository, such as “refs/tags/v1.2.3” or “refs/heads/main”,
+// otherwise the revision as specified, such as a commit hash. Revision
+// returns an empty string when the file system was created using [New] or
+// [NewForCommit].
+func (gfs *FS) Revision() string { return gfs.revision }
+
+// refName returns the fully qualified name of the remote reference the
+// specified revision names in a repository cloned from a remote, or the
+// revision itself if it doesn't name a reference.
+func refName(repo *git.Repository, revision string) string {
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.ReferenceName(revision),
+		plumbing.NewTagReferenceName(revision),
+		plumbing.NewBranchReferenceName(revision),
+		plumbing.ReferenceName("refs/remotes/" + revision),
+	} {
+		if _, err := repo.Reference(name, false); err != nil {
+			continue
+		}
+		// remote-tracking branches are the branches of the remote repository.
+		if branch, ok := strings.CutPrefix(name.String(),
+			"refs/remotes/"+git.DefaultRemoteName+"/"); ok {
+			return plumbing.NewBranchReferenceName(branch).String()
+		}
+		return name.String()
+	}
+	return revision
+}
+
+// revisionObject returns the hash of the annotated tag object if the revision
+// names an annotated tag, otherwise the specified commit hash.
+func revisionObject(repo *git.Repository, revision string, commitHash plumbing.Hash) plumbing.Hash {
+	name, ok := strings.CutPrefix(refName(repo, revision), "refs/tags/")
+	if !ok {
+		return commitHash
+	}
+	ref, err := repo.Tag(name)
+	if err != nil {
+		return commitHash
+	}
+	return ref.Hash()
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/thediveo/gitrepofs/version"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("commit metadata", func() {
+
+	It("reports nothing without a commit", func() {
+		gfs := New(repo, Successful(commit.Tree()), commit.Author.When)
+		Expect(gfs.Commit()).To(BeNil())
+		Expect(gfs.CommitHash()).To(Equal(plumbing.ZeroHash))
+		Expect(gfs.Author()).To(BeZero())
+		Expect(gfs.Committer()).To(BeZero())
+		Expect(gfs.Message()).To(BeEmpty())
+		Expect(gfs.Parents()).To(BeNil())
+		Expect(gfs.Revision()).To(BeEmpty())
+	})
+
+	It("reports the commit", func() {
+		gfs := Successful(NewForCommit(repo, commit))
+		Expect(gfs.Commit()).To(BeIdenticalTo(commit))
+		Expect(gfs.CommitHash()).To(Equal(commit.Hash))
+		Expect(gfs.Author()).To(And(
+			HaveField("Name", "Brian"),
+			HaveField("Email", "brian@palace.herodes")))
+		Expect(gfs.Committer()).To(Equal(commit.Committer))
+		Expect(gfs.Message()).To(Equal("adds canary"))
+		Expect(gfs.Parents()).To(HaveExactElements(commit.ParentHashes))
+		Expect(gfs.Revision()).To(BeEmpty())
+
+		subfs := Successful(gfs.sub("folder"))
+		Expect(subfs.CommitHash()).To(Equal(commit.Hash))
+	})
+
+	DescribeTable("reports the resolved revision",
+		func(ctx context.Context, revision string, expected string) {
+			gfs := Successful(NewForRevision(ctx, tmprepdir, revision))
+			Expect(gfs.Revision()).To(Equal(expected))
+			Expect(gfs.WithArchiveView().Revision()).To(Equal(expected))
+			Expect(Successful(gfs.sub("folder")).Revision()).To(Equal(expected))
+		},
+		Entry(nil, "v1.1.1", "refs/tags/v1.1.1"),
+		Entry(nil, "refs/tags/v1.1.1", "refs/tags/v1.1.1"),
+		Entry(nil, "master", "refs/heads/master"),
+		Entry(nil, "origin/release-1.1", "refs/heads/release-1.1"),
+		Entry(nil, "origin/stable/1.x", "refs/heads/stable/1.x"),
+	)
+
+	It("reports a commit hash revision as is", func(ctx context.Context) {
+		gfs := Successful(NewForRevision(ctx, tmprepdir, commit.Hash.String()))
+		Expect(gfs.Revision()).To(Equal(commit.Hash.String()))
+		Expect(gfs.Message()).To(Equal("adds canary"))
+	})
+
+	It("reports the latest release", func(ctx context.Context) {
+		gfs, latest := Successful2R(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher))
+		Expect(gfs.Revision()).To(Equal(latest.Ref))
+		Expect(gfs.CommitHash()).To(Equal(latest.Commit))
+		Expect(gfs.Committer()).To(BeAssignableToTypeOf(object.Signature{}))
+	})
+
+})
@@ -0,0 +1,159 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// ChangeKind describes how a path changed between two trees.
+type ChangeKind int
+
+const (
+	Added    ChangeKind = iota // path only exists in the new tree.
+	Removed                    // path only exists in the old tree.
+	Modified                   // contents or mode of the path changed.
+	Renamed                    // path got renamed, and maybe modified too.
+)
+
+// String returns the lower-case name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	case Renamed:
+		return "renamed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change describes a changed file between two trees, see [Diff].
+type Change struct {
+	// Kind of change.
+	Kind ChangeKind
+	// Path of the file in the new tree; for removed files, in the old tree.
+	Path string
+	// OldPath of the file in the old tree; empty for added files.
+	OldPath string
+	// OldHash is the blob hash in the old tree; zero for added files.
+	OldHash plumbing.Hash
+	// NewHash is the blob hash in the new tree; zero for removed files.
+	NewHash plumbing.Hash
+	// OldMode is the file mode in the old tree; empty for added files.
+	OldMode filemode.FileMode
+	// NewMode is the file mode in the new tree; empty for removed files.
+	NewMode filemode.FileMode
+
+	change *object.Change
+}
+
+// DiffOption configures [Diff].
+type DiffOption func(*object.DiffTreeOptions)
+
+// WithRenameDetection makes [Diff] detect renamed files, where the old and
+// new file contents need to be at least score percent similar. A zero score
+// uses git's default similarity of 60%. Identical contents always are
+// detected as renames.
+func WithRenameDetection(score uint) DiffOption {
+	return func(o *object.DiffTreeOptions) {
+		o.DetectRenames = true
+		o.RenameScore = score
+		if score == 0 {
+			o.RenameScore = object.DefaultDiffTreeOptions.RenameScore
+		}
+	}
+}
+
+// Diff returns the files added, removed, modified, and optionally renamed
+// between the trees of the file systems a and b, sorted by their paths. Diff
+// compares the git trees directly, so it neither takes text conversion nor
+// archive views into account. The file systems may be from different clones,
+// such as when opening two different revisions using [NewForRevision].
+//
+// Without the [WithRenameDetection] option, renamed files are reported as
+// removed and added files instead.
+func Diff(ctx context.Context, a, b *FS, opts ...DiffOption) ([]Change, error) {
+	o := &object.DiffTreeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, a.tree, b.tree, o)
+	if err != nil {
+		return nil, fmt.Errorf("cannot diff trees, reason: %w", err)
+	}
+	diffs := make([]Change, 0, len(changes))
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return nil, fmt.Errorf("cannot diff trees, reason: %w", err)
+		}
+		c := Change{change: change}
+		switch action {
+		case merkletrie.Insert:
+			c.Kind = Added
+		case merkletrie.Delete:
+			c.Kind = Removed
+		default:
+			c.Kind = Modified
+			if change.From.Name != change.To.Name {
+				c.Kind = Renamed
+			}
+		}
+		if action != merkletrie.Insert {
+			c.Path = change.From.Name
+			c.OldPath = change.From.Name
+			c.OldHash = change.From.TreeEntry.Hash
+			c.OldMode = change.From.TreeEntry.Mode
+		}
+		if action != merkletrie.Delete {
+			c.Path = change.To.Name
+			c.NewHash = change.To.TreeEntry.Hash
+			c.NewMode = change.To.TreeEntry.Mode
+		}
+		diffs = append(diffs, c)
+	}
+	slices.SortFunc(diffs, func(a, b Change) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return diffs, nil
+}
+
+// WriteUnifiedDiff writes the changes of the file in unified diff format, as
+// “git diff” does, using three lines of context. Binary files are only
+// reported as differing.
+func (c Change) WriteUnifiedDiff(ctx context.Context, w io.Writer) error {
+	if c.change == nil {
+		return fmt.Errorf("no diff for change of %q", c.Path)
+	}
+	patch, err := c.change.PatchContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot diff %q, reason: %w", c.Path, err)
+	}
+	return diff.NewUnifiedEncoder(w, diff.DefaultContextLines).Encode(patch)
+}
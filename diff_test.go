@@ -0,0 +1,118 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("diffing trees", func() {
+
+	It("names change kinds", func() {
+		Expect(Added.String()).To(Equal("added"))
+		Expect(Removed.String()).To(Equal("removed"))
+		Expect(Modified.String()).To(Equal("modified"))
+		Expect(Renamed.String()).To(Equal("renamed"))
+		Expect(ChangeKind(42).String()).To(Equal("ChangeKind(42)"))
+	})
+
+	It("reports added files between revisions", func(ctx context.Context) {
+		a := Successful(NewForRevision(ctx, tmprepdir, "v1.0"))
+		b := Successful(NewForRevision(ctx, tmprepdir, "v1.1.1"))
+		changes := Successful(Diff(ctx, a, b))
+		Expect(changes).To(HaveExactElements(
+			HaveField("Path", "fodder/empty"),
+			HaveField("Path", "folder/subfolder/canary.txt"),
+			And(HaveField("Path", "folder/subfolder/schkript.sh"),
+				HaveField("NewMode", filemode.Executable)),
+		))
+		for _, c := range changes {
+			Expect(c.Kind).To(Equal(Added))
+			Expect(c.OldPath).To(BeEmpty())
+			Expect(c.OldHash.IsZero()).To(BeTrue())
+			Expect(c.NewHash.IsZero()).To(BeFalse())
+		}
+
+		changes = Successful(Diff(ctx, b, a))
+		Expect(changes).To(HaveLen(3))
+		Expect(changes[0]).To(And(
+			HaveField("Kind", Removed),
+			HaveField("Path", "fodder/empty"),
+			HaveField("OldPath", "fodder/empty"),
+			HaveField("OldMode", filemode.Regular)))
+		Expect(changes[0].NewHash.IsZero()).To(BeTrue())
+	})
+
+	It("detects renames", func(ctx context.Context) {
+		gfs := Successful(NewForRevision(ctx, tmprepdir, "v1.1.1"))
+		a := Successful(gfs.sub("folder/subfolder"))
+		b := Successful(gfs.sub("folder"))
+
+		changes := Successful(Diff(ctx, a, b))
+		Expect(changes).To(HaveLen(4))
+
+		changes = Successful(Diff(ctx, a, b, WithRenameDetection(0)))
+		Expect(changes).To(ConsistOf(
+			And(HaveField("Kind", Renamed),
+				HaveField("OldPath", "canary.txt"),
+				HaveField("Path", "subfolder/canary.txt")),
+			And(HaveField("Kind", Renamed),
+				HaveField("OldPath", "schkript.sh"),
+				HaveField("Path", "subfolder/schkript.sh")),
+		))
+		Expect(changes[0].OldHash).To(Equal(changes[0].NewHash))
+	})
+
+	It("reports modified files and renders unified diffs", func(ctx context.Context) {
+		monorepodir := localremote.CreateTransientModuleRepo()
+		a := Successful(NewForRevision(ctx, monorepodir, "sub/module/v1.1.0"))
+		b := Successful(NewForRevision(ctx, monorepodir, "sub/module/v3.0.0"))
+		changes := Successful(Diff(ctx, a, b, WithRenameDetection(100)))
+		Expect(changes).To(HaveExactElements(
+			And(HaveField("Kind", Modified),
+				HaveField("Path", "sub/module/go.mod"),
+				HaveField("OldPath", "sub/module/go.mod")),
+			And(HaveField("Kind", Added), HaveField("Path", "sub/module/v2/go.mod")),
+			And(HaveField("Kind", Added), HaveField("Path", "sub/module/v2/module.go")),
+		))
+		Expect(changes[0].OldHash).NotTo(Equal(changes[0].NewHash))
+
+		var sb strings.Builder
+		Expect(changes[0].WriteUnifiedDiff(ctx, &sb)).To(Succeed())
+		Expect(sb.String()).To(And(
+			HavePrefix("diff --git a/sub/module/go.mod b/sub/module/go.mod\n"),
+			ContainSubstring("\n-module example.org/mono/sub/module\n"),
+			ContainSubstring("\n+module example.org/mono/sub/module/v3\n")))
+
+		Expect(Change{Path: "foo"}.WriteUnifiedDiff(ctx, &sb)).NotTo(Succeed())
+	})
+
+	It("stops when the context is cancelled", func(ctx context.Context) {
+		a := Successful(NewForRevision(ctx, tmprepdir, "v1.0"))
+		b := Successful(NewForRevision(ctx, tmprepdir, "master"))
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(Diff(ctx, a, b)).Error().To(HaveOccurred())
+	})
+
+})
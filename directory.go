@@ -18,6 +18,7 @@ import (
 	"errors"
 	"io"
 	"io/fs"
+	"path"
 
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -29,8 +30,12 @@ var _ fs.File = (*Directory)(nil)
 // Directory represents completely unexpectedly a git directory.
 type Directory struct {
 	tree     *object.Tree
+	entries  []object.TreeEntry
 	fileinfo *FileInfo
 	index    int
+
+	gfs  *FS    // optional file system this directory belongs to.
+	name string // path of this directory inside gfs.
 }
 
 // NewDirectory returns a new Directory object representing a git tree.
@@ -40,6 +45,7 @@ func NewDirectory(
 ) *Directory {
 	return &Directory{
 		tree:     tree,
+		entries:  tree.Entries,
 		fileinfo: fileinfo,
 	}
 }
@@ -52,25 +58,33 @@ func (d *Directory) ReadDir(n int) ([]fs.DirEntry, error) {
 		return nil, errors.New("closed directory")
 	}
 	if n <= 0 {
-		n = len(d.tree.Entries) - d.index
+		n = len(d.entries) - d.index
 		if n <= 0 {
+			// nota bene: git trees are never empty, but their (filtered)
+			// entries might well be.
+			if len(d.entries) == 0 {
+				return []fs.DirEntry{}, nil
+			}
 			return nil, io.EOF
 		}
 	}
 	count := n
-	if d.index+count > len(d.tree.Entries) {
-		count = len(d.tree.Entries) - d.index
+	if d.index+count > len(d.entries) {
+		count = len(d.entries) - d.index
 	}
 	if count == 0 {
 		return nil, io.EOF
 	}
-	// nota bene: git trees are never empty.
 	fileinfos := make([]fs.DirEntry, 0, count)
 	for ; count > 0; count-- {
 		var size int64
-		entry := d.tree.Entries[d.index]
+		entry := d.entries[d.index]
 		if entry.Mode == filemode.Regular || entry.Mode == filemode.Executable {
-			size, _ = d.tree.Size(entry.Name)
+			if d.gfs != nil {
+				size, _ = d.gfs.fileSize(path.Join(d.name, entry.Name), entry)
+			} else {
+				size, _ = d.tree.Size(entry.Name)
+			}
 		}
 		fileinfos = append(fileinfos,
 			NewDirEntry(entry, size, d.fileinfo.mtime))
@@ -6,11 +6,84 @@ The main envisioned use case is for “go generate”-based updates from upstrea
 repositories to fetch the latest C definitions without the need to integrate
 upstream C libraries.
 
-		remoteURL := "https://gohub.org/froozle/baduzle"
-		latest, latestref, err := version.LatestReleaseTag(context.Background(),
-	      remoteURL, version.SemverMatcher)
-		gfs, err := NewForRevision(context.Background(), remoteURL, latestref)
-		contents, err := fs.ReadFile(gfs, "some/useful/file.h")
+	remoteURL := "https://gohub.org/froozle/baduzle"
+	gfs, latest, err := OpenLatest(context.Background(),
+	    remoteURL, version.SemverTagMatcher)
+	contents, err := fs.ReadFile(gfs, "some/useful/file.h")
+
+[OpenLatest] lists the remote references only once and then fetches exactly the
+commit of the latest release, avoiding races with tags being moved in between
+determining the latest release and fetching it.
+
+# Text Conversion
+
+By default, files are served byte-for-byte as stored in their git blobs. Use
+[FS.WithTextConversion] to instead get the files as “git checkout” would
+produce them, applying the “text”, “eol”, and “working-tree-encoding”
+attributes from any .gitattributes files.
+
+# Archive View
+
+Use [FS.WithArchiveView] to get a view that behaves like “git archive”: files
+and directories with the “export-ignore” attribute are hidden, and files with
+the “export-subst” attribute get their “$Format:...$” placeholders expanded.
+
+# History
+
+Use [NewHistory] to get a [HistoryFS] presenting all tags, branches, and
+commits of a remote repository as “tags/<name>/...”, “branches/<name>/...”,
+and “commits/<hash>/...” directories, all served from a single clone:
+
+	h, err := NewHistory(context.Background(), remoteURL)
+	old, err := fs.ReadFile(h, "tags/v1.0.0/include/foo.h")
+	new, err := fs.ReadFile(h, "tags/v1.1.0/include/foo.h")
+
+Use [Log] to find out when and why a particular file or directory changed,
+[Blame] to find out which commits introduced the individual lines of a file,
+and [FS.Commit] and [FS.Revision] to learn which commit a file system
+represents.
+
+# Diffs
+
+Use [Diff] to find the files added, removed, modified, or renamed between two
+revisions, such as before regenerating code from an updated upstream release,
+and [Change.WriteUnifiedDiff] to render the changes of individual files.
+
+# Searching
+
+Use [Grep] to search the contents of all files in a revision for a regular
+expression, such as when looking for the header defining a particular macro.
+
+# Overlays
+
+Use [Overlay] to carry local changes on top of a revision, such as a few
+patched upstream headers: files in an upper file system, such as a local
+directory, take precedence, whiteout files hide files of the revision, and
+unified diff patches added using [Overlay.AddPatches] get applied at read time.
+
+# Integrity
+
+Use [WithExpectedCommit] or [WithExpectedTree] to fail when a revision
+doesn't resolve to the expected commit or tree anymore, such as after a tag
+has been moved, and [remote.WithVerifier] to require signed tags or commits.
+[Verify] re-hashes all objects of a file system to detect tampering with the
+repository objects.
+
+# Serving over HTTP
+
+Use [NewHandler] to serve a revision over HTTP, such as upstream
+documentation, with the blob and tree hashes as entity tags:
+
+	http.Handle("/docs/", http.StripPrefix("/docs", gitrepofs.NewHandler(gfs)))
+
+# Go Modules
+
+Use [OpenGoModule] to get the latest release of a Go module in a (mono)
+repository, with tags such as “sub/module/v1.2.3”. It follows the Go module
+rules for “+incompatible” versions and “/vN” major version suffixes, returning
+an [FS] rooted at the module's directory:
+
+	gfs, tag, err := OpenGoModule(context.Background(), remoteURL, "sub/module/v2")
 
 # The fs.FS Zoo
 
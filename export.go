@@ -0,0 +1,201 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ArchiveOptions control how [WriteTar] and [WriteZip] write the files and
+// directories of an [FS] into an archive.
+type ArchiveOptions struct {
+	// Path of the directory to write into the archive; defaults to the root
+	// directory “.”. The names in the archive are relative to this directory.
+	Path string
+	// Prefix gets prepended verbatim to all names in the archive, as with “git
+	// archive --prefix”. For a directory prefix, don't forget the trailing
+	// slash, such as in “foo-1.2.3/”.
+	Prefix string
+	// ModTime is the modification time of all archive entries; defaults to
+	// the modification time of the FS.
+	ModTime time.Time
+}
+
+// archiveEntry describes a single file, directory, or symbolic link to be
+// written into an archive.
+type archiveEntry struct {
+	name  string      // name inside the archive.
+	mode  fs.FileMode // type and permission bits.
+	entry object.TreeEntry
+	path  string // path inside the FS.
+}
+
+// walkArchive walks the (sub) tree specified in the options, calling fn for
+// each entry to be written into an archive. The entries are passed in a
+// deterministic order, that is, git's tree order.
+func (gfs *FS) walkArchive(opts *ArchiveOptions, fn func(ae archiveEntry) error) error {
+	dir := opts.Path
+	if dir == "" {
+		dir = "."
+	}
+	if !fs.ValidPath(dir) {
+		return &fs.PathError{Op: "archive", Path: dir, Err: fs.ErrInvalid}
+	}
+	return gfs.walkTree(dir, func(name string, entry object.TreeEntry) error {
+		rel := name
+		if dir != "." {
+			rel = strings.TrimPrefix(name, dir+"/")
+		}
+		ae := archiveEntry{
+			name:  opts.Prefix + rel,
+			entry: entry,
+			path:  name,
+		}
+		switch entry.Mode {
+		case filemode.Regular, filemode.Deprecated:
+			ae.mode = 0o644
+		case filemode.Executable:
+			ae.mode = 0o755
+		case filemode.Symlink:
+			ae.mode = fs.ModeSymlink | 0o777
+		case filemode.Dir, filemode.Submodule:
+			// as “git archive” does, submodules become empty directories.
+			ae.name += "/"
+			ae.mode = fs.ModeDir | 0o755
+		default:
+			return nil
+		}
+		return fn(ae)
+	})
+}
+
+// normalizedArchiveOptions returns a copy of the specified options with the
+// defaults filled in.
+func (gfs *FS) normalizedArchiveOptions(opts *ArchiveOptions) *ArchiveOptions {
+	o := ArchiveOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.ModTime.IsZero() {
+		o.ModTime = gfs.mtime
+	}
+	// archive formats work on whole seconds, and we want the archives to be
+	// independent of the local time zone.
+	o.ModTime = o.ModTime.Truncate(time.Second).UTC()
+	return &o
+}
+
+// WriteTar writes the files, directories, and symbolic links of the specified
+// file system into w as a tar archive. The tar archive is reproducible
+// byte-for-byte for the same git tree and options: entries are written in
+// git's tree order, with the same modification time, without any user or
+// group information, and with only the permission bits as recorded by git.
+//
+// WriteTar honors archive and text conversion views, see [FS.WithArchiveView]
+// and [FS.WithTextConversion].
+func WriteTar(w io.Writer, gfs *FS, opts *ArchiveOptions) error {
+	opts = gfs.normalizedArchiveOptions(opts)
+	tw := tar.NewWriter(w)
+	err := gfs.walkArchive(opts, func(ae archiveEntry) error {
+		hdr := &tar.Header{
+			Name:    ae.name,
+			Mode:    int64(ae.mode.Perm()),
+			ModTime: opts.ModTime,
+			Format:  tar.FormatPAX,
+		}
+		if ae.mode.IsDir() {
+			hdr.Typeflag = tar.TypeDir
+			return tw.WriteHeader(hdr)
+		}
+		r, size, err := gfs.openBlob(ae.path, ae.entry)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = r.Close() }()
+		if ae.mode&fs.ModeSymlink != 0 {
+			target, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = string(target)
+			return tw.WriteHeader(hdr)
+		}
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = size
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, r)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write tar archive, reason: %w", err)
+	}
+	return tw.Close()
+}
+
+// WriteZip writes the files, directories, and symbolic links of the specified
+// file system into w as a zip archive. The zip archive is reproducible
+// byte-for-byte for the same git tree and options: entries are written in
+// git's tree order, with the same modification time, and with only the
+// permission bits as recorded by git.
+//
+// WriteZip honors archive and text conversion views, see [FS.WithArchiveView]
+// and [FS.WithTextConversion].
+func WriteZip(w io.Writer, gfs *FS, opts *ArchiveOptions) error {
+	opts = gfs.normalizedArchiveOptions(opts)
+	zw := zip.NewWriter(w)
+	err := gfs.walkArchive(opts, func(ae archiveEntry) error {
+		hdr := &zip.FileHeader{
+			Name:     ae.name,
+			Method:   zip.Deflate,
+			Modified: opts.ModTime,
+		}
+		hdr.SetMode(ae.mode)
+		if ae.mode.IsDir() {
+			hdr.Method = zip.Store
+			_, err := zw.CreateHeader(hdr)
+			return err
+		}
+		r, _, err := gfs.openBlob(ae.path, ae.entry)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = r.Close() }()
+		if ae.mode&fs.ModeSymlink != 0 {
+			hdr.Method = zip.Store
+		}
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(fw, r)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write zip archive, reason: %w", err)
+	}
+	return zw.Close()
+}
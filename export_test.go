@@ -0,0 +1,141 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("exporting archives", func() {
+
+	var gfs *FS
+
+	BeforeEach(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "master"))
+	})
+
+	Context("tar", func() {
+
+		It("writes a reproducible tar archive", func() {
+			var first, second bytes.Buffer
+			Expect(WriteTar(&first, gfs, nil)).To(Succeed())
+			Expect(WriteTar(&second, gfs, nil)).To(Succeed())
+			Expect(first.Bytes()).To(Equal(second.Bytes()))
+		})
+
+		It("writes a sub tree with prefix, modes, and symbolic links", func() {
+			var buf bytes.Buffer
+			Expect(WriteTar(&buf, gfs, &ArchiveOptions{
+				Path:   "folder",
+				Prefix: "foo-1.2.3/",
+			})).To(Succeed())
+
+			headers := map[string]*tar.Header{}
+			contents := map[string]string{}
+			tr := tar.NewReader(&buf)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				Expect(err).NotTo(HaveOccurred())
+				headers[hdr.Name] = hdr
+				contents[hdr.Name] = string(Successful(io.ReadAll(tr)))
+			}
+			Expect(headers).To(HaveLen(4))
+			Expect(headers).To(HaveKeyWithValue("foo-1.2.3/subfolder/", And(
+				HaveField("Typeflag", byte(tar.TypeDir)),
+				HaveField("Mode", int64(0o755)))))
+			Expect(headers).To(HaveKeyWithValue("foo-1.2.3/subfolder/canary.txt", And(
+				HaveField("Typeflag", byte(tar.TypeReg)),
+				HaveField("Mode", int64(0o644)),
+				HaveField("ModTime", BeTemporally("==", gfs.mtime.Truncate(time.Second))),
+				HaveField("Uname", ""))))
+			Expect(headers).To(HaveKeyWithValue("foo-1.2.3/subfolder/schkript.sh",
+				HaveField("Mode", int64(0o755))))
+			Expect(headers).To(HaveKeyWithValue("foo-1.2.3/subfolder/link", And(
+				HaveField("Typeflag", byte(tar.TypeSymlink)),
+				HaveField("Linkname", "canary.txt"))))
+			Expect(contents).To(HaveKeyWithValue("foo-1.2.3/subfolder/canary.txt",
+				ContainSubstring("chirp!")))
+		})
+
+		It("honors archive views", func() {
+			var buf bytes.Buffer
+			Expect(WriteTar(&buf, gfs.WithArchiveView(), nil)).To(Succeed())
+			tr := tar.NewReader(&buf)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hdr.Name).NotTo(HavePrefix("fodder"))
+			}
+		})
+
+		It("rejects invalid and missing paths", func() {
+			Expect(WriteTar(io.Discard, gfs, &ArchiveOptions{Path: "/foo"})).
+				To(MatchError(fs.ErrInvalid))
+			Expect(WriteTar(io.Discard, gfs, &ArchiveOptions{Path: "foo"})).
+				To(MatchError(fs.ErrNotExist))
+		})
+
+	})
+
+	Context("zip", func() {
+
+		It("writes a reproducible zip archive", func() {
+			var first, second bytes.Buffer
+			Expect(WriteZip(&first, gfs, nil)).To(Succeed())
+			Expect(WriteZip(&second, gfs, nil)).To(Succeed())
+			Expect(first.Bytes()).To(Equal(second.Bytes()))
+		})
+
+		It("writes a sub tree with modes and symbolic links", func() {
+			var buf bytes.Buffer
+			Expect(WriteZip(&buf, gfs, &ArchiveOptions{
+				Path: "folder/subfolder",
+			})).To(Succeed())
+
+			zr := Successful(zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+			files := map[string]*zip.File{}
+			for _, f := range zr.File {
+				files[f.Name] = f
+			}
+			Expect(files).To(HaveLen(3))
+			Expect(files["schkript.sh"].Mode().Perm()).To(Equal(fs.FileMode(0o755)))
+			Expect(files["canary.txt"].Mode().Perm()).To(Equal(fs.FileMode(0o644)))
+			Expect(files["link"].Mode() & fs.ModeSymlink).NotTo(BeZero())
+
+			r := Successful(files["link"].Open())
+			defer func() { _ = r.Close() }()
+			Expect(io.ReadAll(r)).To(Equal([]byte("canary.txt")))
+		})
+
+	})
+
+})
@@ -0,0 +1,174 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ExtractOptions control how [Extract] materializes the files of an [FS] in
+// the local file system.
+type ExtractOptions struct {
+	// Manifest is the name of the manifest file to write into the
+	// destination directory, if not empty. The manifest records the
+	// extracted files and their hashes for later verification using
+	// [VerifyManifest].
+	Manifest string
+}
+
+// Extract materializes the files, directories, and symbolic links of the
+// srcDir directory in the specified file system into the dstDir directory in
+// the local file system, similar to a “git checkout” without any work tree.
+// Regular files get 0644 permissions and executable files get 0755
+// permissions, independent of the current umask.
+//
+// The destination directory is replaced as a whole: the files first get
+// written to a temporary directory next to dstDir that then gets renamed to
+// dstDir. Thus, any stale files from a previous extraction are removed and an
+// unsuccessful extraction leaves a previous extraction untouched.
+//
+// Extract returns a manifest of the extracted files; this manifest is
+// additionally written into dstDir if [ExtractOptions.Manifest] is set.
+func Extract(ctx context.Context, gfs *FS, srcDir, dstDir string, opts *ExtractOptions) (*Manifest, error) {
+	if opts == nil {
+		opts = &ExtractOptions{}
+	}
+	dstDir = filepath.Clean(dstDir)
+	parent := filepath.Dir(dstDir)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create parent directory of %q, reason: %w",
+			dstDir, err)
+	}
+	tmpDir, err := os.MkdirTemp(parent, "."+filepath.Base(dstDir)+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temporary extraction directory, reason: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	if err := os.Chmod(tmpDir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot extract, reason: %w", err)
+	}
+
+	manifest := &Manifest{Files: []ManifestEntry{}}
+	if gfs.commit != nil {
+		manifest.Commit = gfs.commit.Hash.String()
+	}
+	err = gfs.walkArchive(&ArchiveOptions{Path: srcDir}, func(ae archiveEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := filepath.Join(tmpDir, filepath.FromSlash(strings.TrimSuffix(ae.name, "/")))
+		if !withinDir(tmpDir, name) {
+			return &fs.PathError{Op: "extract", Path: ae.name, Err: fs.ErrInvalid}
+		}
+		if ae.mode.IsDir() {
+			return os.Mkdir(name, ae.mode.Perm())
+		}
+		hash, err := gfs.extractFile(name, ae)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Path: ae.name,
+			Mode: ae.entry.Mode.String(),
+			Hash: hash.String(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot extract %q into %q, reason: %w",
+			srcDir, dstDir, err)
+	}
+	if opts.Manifest != "" {
+		if err := WriteManifest(filepath.Join(tmpDir, opts.Manifest), manifest); err != nil {
+			return nil, err
+		}
+	}
+	if err := replaceDir(tmpDir, dstDir); err != nil {
+		return nil, fmt.Errorf("cannot extract into %q, reason: %w", dstDir, err)
+	}
+	return manifest, nil
+}
+
+// extractFile writes a single file or symbolic link into the local file
+// system, returning the git blob hash of the written contents.
+func (gfs *FS) extractFile(name string, ae archiveEntry) (plumbing.Hash, error) {
+	r, size, err := gfs.openBlob(ae.path, ae.entry)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer func() { _ = r.Close() }()
+	hasher := plumbing.NewHasher(plumbing.BlobObject, size)
+	if ae.mode&fs.ModeSymlink != 0 {
+		target, err := io.ReadAll(io.TeeReader(r, hasher))
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if err := os.Symlink(string(target), name); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return hasher.Sum(), nil
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, ae.mode.Perm())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	_, err = io.Copy(io.MultiWriter(f, hasher), r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	// make sure to set the correct permissions, regardless of the umask.
+	if err := os.Chmod(name, ae.mode.Perm()); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return hasher.Sum(), nil
+}
+
+// withinDir returns true if the cleaned name is inside the dir directory.
+func withinDir(dir, name string) bool {
+	rel, err := filepath.Rel(dir, name)
+	return err == nil && rel != "." && filepath.IsLocal(rel)
+}
+
+// replaceDir replaces the dst directory (if any) with the src directory,
+// using renames. The previous dst directory gets removed afterwards on a best
+// effort basis, as the replacement already succeeded at this point.
+func replaceDir(src, dst string) error {
+	old := src + ".old"
+	err := os.Rename(dst, old)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return os.Rename(src, dst)
+	case err != nil:
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		_ = os.Rename(old, dst)
+		return err
+	}
+	_ = os.RemoveAll(old)
+	return nil
+}
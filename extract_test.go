@@ -0,0 +1,133 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("extracting into local directories", func() {
+
+	var gfs *FS
+	var dstDir string
+
+	BeforeEach(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "master"))
+		tmpDir := Successful(os.MkdirTemp("", "gitrepofs-extract-*"))
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+		dstDir = filepath.Join(tmpDir, "third_party", "upstream")
+	})
+
+	It("extracts a sub tree with modes and symbolic links", func(ctx context.Context) {
+		m := Successful(Extract(ctx, gfs, "folder", dstDir, nil))
+		Expect(m.Commit).To(Equal(gfs.commit.Hash.String()))
+		Expect(m.Files).To(HaveLen(3))
+
+		Expect(filepath.Join(dstDir, "subfolder/canary.txt")).To(BeARegularFile())
+		Expect(os.ReadFile(filepath.Join(dstDir, "subfolder/canary.txt"))).To(
+			ContainSubstring("chirp!"))
+		Expect(Successful(os.Stat(filepath.Join(dstDir, "subfolder/schkript.sh"))).Mode().Perm()).To(
+			Equal(os.FileMode(0o755)))
+		Expect(os.Readlink(filepath.Join(dstDir, "subfolder/link"))).To(Equal("canary.txt"))
+
+		entry := Successful(gfs.tree.FindEntry("folder/subfolder/canary.txt"))
+		Expect(m.Files).To(ContainElement(ManifestEntry{
+			Path: "subfolder/canary.txt",
+			Mode: "0100644",
+			Hash: entry.Hash.String(),
+		}))
+	})
+
+	It("removes stale files and verifies against the manifest", func(ctx context.Context) {
+		Expect(Extract(ctx, gfs, ".", dstDir, nil)).Error().NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(dstDir, "stale"), []byte("stale"), 0o644)).To(Succeed())
+
+		Expect(Extract(ctx, gfs, "folder", dstDir, &ExtractOptions{
+			Manifest: "MANIFEST.json",
+		})).Error().NotTo(HaveOccurred())
+		Expect(filepath.Join(dstDir, "stale")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dstDir, "README")).NotTo(BeAnExistingFile())
+		Expect(Successful(os.ReadDir(filepath.Dir(dstDir)))).To(HaveLen(1))
+
+		m := Successful(ReadManifest(filepath.Join(dstDir, "MANIFEST.json")))
+		Expect(VerifyManifest(dstDir, m, "MANIFEST.json")).To(Succeed())
+
+		Expect(os.WriteFile(filepath.Join(dstDir, "subfolder/canary.txt"), []byte("croak!"), 0o644)).To(Succeed())
+		Expect(os.Remove(filepath.Join(dstDir, "subfolder/link"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dstDir, "stale"), []byte("stale"), 0o644)).To(Succeed())
+		err := VerifyManifest(dstDir, m, "MANIFEST.json")
+		Expect(err).To(MatchError(ErrManifestMismatch))
+		Expect(err).To(MatchError(ContainSubstring(`changed file "subfolder/canary.txt"`)))
+		Expect(err).To(MatchError(ContainSubstring(`missing file "subfolder/link"`)))
+		Expect(err).To(MatchError(ContainSubstring(`unexpected file "stale"`)))
+	})
+
+	It("leaves a previous extraction untouched on failure", func(ctx context.Context) {
+		Expect(Extract(ctx, gfs, "folder", dstDir, nil)).Error().NotTo(HaveOccurred())
+		Expect(Extract(ctx, gfs, "nonexisting", dstDir, nil)).Error().To(HaveOccurred())
+		Expect(filepath.Join(dstDir, "subfolder/canary.txt")).To(BeARegularFile())
+		Expect(Successful(os.ReadDir(filepath.Dir(dstDir)))).To(HaveLen(1))
+	})
+
+	It("rejects crafted trees escaping the destination", func(ctx context.Context) {
+		repo := Successful(git.Init(memory.NewStorage(), nil))
+		store := func(o interface {
+			Encode(plumbing.EncodedObject) error
+		}) plumbing.Hash {
+			obj := repo.Storer.NewEncodedObject()
+			Expect(o.Encode(obj)).To(Succeed())
+			return Successful(repo.Storer.SetEncodedObject(obj))
+		}
+		blob := &plumbing.MemoryObject{}
+		blob.SetType(plumbing.BlobObject)
+		Expect(blob.Write([]byte("escaped!\n"))).Error().NotTo(HaveOccurred())
+		blobHash := Successful(repo.Storer.SetEncodedObject(blob))
+
+		for _, name := range []string{"../../escaped.txt", "..", ".", "sub/escaped.txt"} {
+			treeHash := store(&object.Tree{Entries: []object.TreeEntry{
+				{Name: name, Mode: filemode.Regular, Hash: blobHash},
+			}})
+			crafted := New(repo, Successful(repo.TreeObject(treeHash)), time.Now())
+			Expect(Extract(ctx, crafted, ".", dstDir, nil)).Error().To(MatchError(fs.ErrInvalid))
+			Expect(dstDir).NotTo(BeAnExistingFile())
+			Expect(Successful(os.ReadDir(filepath.Dir(dstDir)))).To(BeEmpty())
+			Expect(filepath.Join(filepath.Dir(dstDir), "escaped.txt")).NotTo(BeAnExistingFile())
+		}
+	})
+
+	It("stops when the context is cancelled", func(ctx context.Context) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(Extract(ctx, gfs, ".", dstDir, nil)).Error().To(MatchError(context.Canceled))
+		Expect(dstDir).NotTo(BeAnExistingFile())
+	})
+
+})
@@ -15,10 +15,13 @@
 package gitrepofs
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
@@ -26,15 +29,27 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/thediveo/gitrepofs/remote"
 )
 
-var _ fs.FS = (*FS)(nil)
+var (
+	_ fs.FS    = (*FS)(nil)
+	_ fs.SubFS = (*FS)(nil)
+)
 
 // FS provides a view into a specific git tree.
 type FS struct {
 	repo  *git.Repository
 	tree  *object.Tree
 	mtime time.Time
+
+	commit   *object.Commit // optional commit the tree belongs to.
+	revision string         // optional revision the commit was resolved from.
+	prefix   string         // path of the tree inside the commit's tree, if a subtree.
+
+	textconv  bool      // apply text and encoding attributes when reading files.
+	archive   bool      // apply export attributes, as "git archive" does.
+	attrcache *sync.Map // dir path -> git attributes stack.
 }
 
 // NewForRevision returns a [fs.FS] git repository file system object that
@@ -47,17 +62,27 @@ type FS struct {
 //   - HEAD
 //   - branch
 //   - tag
+//   - commit hash, such as the commit hash returned by
+//     [github.com/thediveo/gitrepofs/version.LatestRelease], for fully pinned
+//     fetches.
 //   - ...
-func NewForRevision(ctx context.Context, remoteURL string, revision string) (fs.FS, error) {
-	repo, err := git.Clone(
-		memory.NewStorage(),
-		nil,
-		&git.CloneOptions{
-			URL: remoteURL,
-		})
+//
+// The returned file system reports the commit and the reference the revision
+// resolved to, see [FS.Commit] and [FS.Revision].
+//
+// Use [WithExpectedCommit] or [WithExpectedTree] to pin the revision to a
+// specific commit or tree, [Verify] to check the fetched objects,
+// [remote.WithVerifier] to require a signed annotated tag or commit, and the
+// options from the [remote] package to configure authentication, proxies, CA
+// bundles, and timeouts.
+func NewForRevision(ctx context.Context, remoteURL string, revision string, opts ...Option) (*FS, error) {
+	o, err := newOptions(opts)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"cannot clone remote repository %q", remoteURL)
+		return nil, err
+	}
+	repo, err := cloneRemote(ctx, remoteURL, "", o)
+	if err != nil {
+		return nil, err
 	}
 	commitHash, err := repo.ResolveRevision(plumbing.Revision(revision))
 	if err != nil {
@@ -65,28 +90,86 @@ func NewForRevision(ctx context.Context, remoteURL string, revision string) (fs.
 			"no such revision %q in remote repository %q",
 			revision, remoteURL)
 	}
+	if err := checkCommit(o, *commitHash); err != nil {
+		return nil, fmt.Errorf(
+			"revision %q in remote repository %q %w",
+			revision, remoteURL, err)
+	}
 	commit, err := repo.CommitObject(*commitHash)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"invalid commit hash for reference %q in remote repository %q",
 			revision, remoteURL)
 	}
-	tree, err := commit.Tree()
+	if err := checkTree(o, commit); err != nil {
+		return nil, fmt.Errorf(
+			"revision %q in remote repository %q %w",
+			revision, remoteURL, err)
+	}
+	if err := o.Verify(repo, revisionObject(repo, revision, *commitHash)); err != nil {
+		return nil, fmt.Errorf(
+			"cannot verify revision %q in remote repository %q, reason: %w",
+			revision, remoteURL, err)
+	}
+	gfs, err := NewForCommit(repo, commit)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"invalid tree hash for reference %q  in remote repository %q",
 			revision, remoteURL)
 	}
-	return New(repo, tree, commit.Author.When), nil
+	gfs.revision = refName(repo, revision)
+	return gfs, nil
+}
+
+// cloneRemote clones the specified remote repository into memory. If ref isn't
+// empty, then only this single reference gets cloned.
+func cloneRemote(ctx context.Context, remoteURL string, ref plumbing.ReferenceName, o *remote.Options) (*git.Repository, error) {
+	cloneOpts := o.CloneOptions(remoteURL)
+	if ref != "" {
+		cloneOpts.ReferenceName = ref
+		cloneOpts.SingleBranch = true
+	}
+	return clone(ctx, cloneOpts, o)
+}
+
+// clone clones a remote repository into memory using the specified clone
+// options.
+func clone(ctx context.Context, cloneOpts *git.CloneOptions, o *remote.Options) (*git.Repository, error) {
+	ctx, cancel := o.Context(ctx)
+	defer cancel()
+	repo, err := git.CloneContext(
+		ctx,
+		memory.NewStorage(),
+		nil,
+		cloneOpts)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot clone remote repository %q, reason: %w", cloneOpts.URL, err)
+	}
+	return repo, nil
+}
+
+// NewForCommit returns a [fs.FS] for the tree of the specified commit in the
+// git repository object, using the commit's author time as the modification
+// time.
+func NewForCommit(repo *git.Repository, commit *object.Commit) (*FS, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tree hash for commit %s", commit.Hash)
+	}
+	gfs := New(repo, tree, commit.Author.When)
+	gfs.commit = commit
+	return gfs, nil
 }
 
 // New returns a [fs.FS] for the specified tree of the git repository object,
 // and using the specified modification time.
-func New(repo *git.Repository, tree *object.Tree, mtime time.Time) fs.FS {
+func New(repo *git.Repository, tree *object.Tree, mtime time.Time) *FS {
 	return &FS{
-		repo:  repo,
-		tree:  tree,
-		mtime: mtime,
+		repo:      repo,
+		tree:      tree,
+		mtime:     mtime,
+		attrcache: &sync.Map{},
 	}
 }
 
@@ -121,6 +204,9 @@ func (gfs *FS) Open(name string) (fs.File, error) {
 		entry.Mode = filemode.Dir
 	} else {
 		e, err := gfs.tree.FindEntry(name)
+		if err == nil && gfs.exportIgnored(name) {
+			err = object.ErrEntryNotFound
+		}
 		if err != nil { // reports object.ErrDirectoryNotFound, ErrEntryNotFound
 			return nil, &fs.PathError{
 				Op:   "open",
@@ -143,6 +229,61 @@ func (gfs *FS) Open(name string) (fs.File, error) {
 	}
 }
 
+// Sub returns an [FS] corresponding to the subtree rooted at dir, keeping any
+// text conversion and archive view settings. When Sub returns an error, it is
+// of type [*fs.PathError] with the Op field set to "sub".
+//
+// Please note that git attributes from .gitattributes files outside the
+// subtree are not taken into account anymore, similar to "git archive" with a
+// tree-ish such as "HEAD:dir".
+func (gfs *FS) Sub(dir string) (fs.FS, error) {
+	return gfs.sub(dir)
+}
+
+// sub returns the [FS] for the subtree rooted at dir.
+func (gfs *FS) sub(dir string) (*FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{
+			Op:   "sub",
+			Path: dir,
+			Err:  fs.ErrInvalid,
+		}
+	}
+	if dir == "." {
+		return gfs, nil
+	}
+	entry, err := gfs.tree.FindEntry(dir)
+	if err != nil || gfs.exportIgnored(dir) {
+		return nil, &fs.PathError{
+			Op:   "sub",
+			Path: dir,
+			Err:  fs.ErrNotExist,
+		}
+	}
+	if entry.Mode != filemode.Dir {
+		return nil, &fs.PathError{
+			Op:   "sub",
+			Path: dir,
+			Err:  fs.ErrInvalid,
+		}
+	}
+	tree, err := gfs.repo.TreeObject(entry.Hash)
+	if err != nil {
+		return nil, &fs.PathError{
+			Op:   "sub",
+			Path: dir,
+			Err:  fs.ErrNotExist, // now that is embarrassing
+		}
+	}
+	subfs := New(gfs.repo, tree, gfs.mtime)
+	subfs.commit = gfs.commit
+	subfs.revision = gfs.revision
+	subfs.prefix = path.Join(gfs.prefix, dir)
+	subfs.textconv = gfs.textconv
+	subfs.archive = gfs.archive
+	return subfs, nil
+}
+
 // openFile returns a File object for the specified file name+path. The
 // name+path must have been validated before using [fs.ValidPath].
 func (gfs *FS) openFile(name string, entry object.TreeEntry) (fs.File, error) {
@@ -154,6 +295,9 @@ func (gfs *FS) openFile(name string, entry object.TreeEntry) (fs.File, error) {
 			Err:  fs.ErrNotExist, // now that is embarrassing
 		}
 	}
+	if gfs.converts() {
+		return gfs.openConvertedFile(name, entry, blob)
+	}
 	f := NewFile(NewFileInfo(entry, blob.Size, gfs.mtime), blob)
 	if f == nil {
 		return nil, &fs.PathError{
@@ -188,5 +332,77 @@ func (gfs *FS) openDir(name string, h plumbing.Hash) (f fs.File, err error) {
 		Mode: filemode.Dir,
 		Hash: tree.ID(), // ... albeit we actually don't need it.
 	}
-	return NewDirectory(tree, NewFileInfo(entry, 0, gfs.mtime)), nil
+	d := NewDirectory(tree, NewFileInfo(entry, 0, gfs.mtime))
+	d.gfs = gfs
+	d.name = name
+	if gfs.archive {
+		d.entries = gfs.exportedEntries(name, tree)
+	}
+	return d, nil
+}
+
+// openConvertedFile returns a File object for the specified file name+path,
+// with its contents converted according to the applicable git attributes.
+func (gfs *FS) openConvertedFile(name string, entry object.TreeEntry, blob *object.Blob) (fs.File, error) {
+	contents, err := gfs.readConverted(name, blob)
+	if err != nil {
+		return nil, &fs.PathError{
+			Op:   "open",
+			Path: name,
+			Err:  err,
+		}
+	}
+	return &File{
+		fileinfo: NewFileInfo(entry, int64(len(contents)), gfs.mtime),
+		r:        io.NopCloser(bytes.NewReader(contents)),
+	}, nil
+}
+
+// converts returns true if file contents might need conversion when reading
+// them.
+func (gfs *FS) converts() bool { return gfs.textconv || gfs.archive }
+
+// readConverted returns the contents of the specified blob, converted
+// according to the git attributes applicable to the specified file name+path.
+func (gfs *FS) readConverted(name string, blob *object.Blob) ([]byte, error) {
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	attrs := gfs.attributesFor(name)
+	if gfs.textconv {
+		contents, err = convertText(name, contents, attrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if gfs.archive && gfs.commit != nil {
+		if subst, ok := attrs["export-subst"]; ok && subst.IsSet() {
+			contents = expandFormat(contents, gfs.commit)
+		}
+	}
+	return contents, nil
+}
+
+// fileSize returns the size of the specified regular or executable file, as
+// read through this file system. This is either the blob size, or the size
+// after converting the blob contents.
+func (gfs *FS) fileSize(name string, entry object.TreeEntry) (int64, error) {
+	blob, err := gfs.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return 0, err
+	}
+	if !gfs.converts() {
+		return blob.Size, nil
+	}
+	contents, err := gfs.readConverted(name, blob)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(contents)), nil
 }
@@ -16,11 +16,18 @@ package gitrepofs
 
 import (
 	"context"
+	"errors"
 	"io/fs"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 
+	"github.com/thediveo/gitrepofs/remote"
+	"github.com/thediveo/gitrepofs/signature"
+	"github.com/thediveo/gitrepofs/test/localremote"
+	"github.com/thediveo/gitrepofs/version"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/thediveo/success"
@@ -101,6 +108,50 @@ var _ = Describe("git directories", func() {
 			To(HaveOccurred())
 	})
 
+	It("passes remote options on", func(ctx context.Context) {
+		Expect(NewForRevision(ctx, tmprepdir, "master", remote.WithTimeout(time.Nanosecond))).Error().
+			To(MatchError(context.DeadlineExceeded))
+		Expect(NewForRevision(ctx, tmprepdir, "master", remote.WithTimeout(time.Minute))).Error().
+			NotTo(HaveOccurred())
+	})
+
+	DescribeTable("rejects malformed expected commits",
+		func(ctx context.Context, hash string) {
+			Expect(NewForRevision(ctx, tmprepdir, "master", WithExpectedCommit(hash))).
+				Error().To(MatchError(ErrInvalidHash))
+			Expect(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher, WithExpectedCommit(hash))).
+				Error().To(MatchError(ErrInvalidHash))
+		},
+		Entry("empty", ""),
+		Entry("version", "v1.2.3"),
+		Entry("leading space", " 0123abcd"),
+		Entry("prefixed", "sha1:0123"),
+		Entry("short", "0123abcd"),
+		Entry("zero", "0000000000000000000000000000000000000000"),
+	)
+
+	It("returns an fs.FS for a pinned commit", func(ctx context.Context) {
+		latest := Successful(version.LatestRelease(ctx, tmprepdir, version.SemverTagMatcher))
+		gfs := Successful(NewForRevision(ctx, tmprepdir, latest.Commit.String()))
+		Expect(gfs.commit.Hash).To(Equal(commit.Hash))
+		Expect(fs.ReadFile(gfs, "folder/subfolder/canary.txt")).Error().NotTo(HaveOccurred())
+	})
+
+	It("returns sub trees", func() {
+		subfs := Successful(fs.Sub(gfs, "folder"))
+		Expect(subfs).To(BeAssignableToTypeOf(&FS{}))
+		Expect(fs.ReadFile(subfs, "subfolder/canary.txt")).To(ContainSubstring("chirp!"))
+		Expect(fs.ReadDir(subfs, ".")).To(ConsistOf(HaveField("Name()", "subfolder")))
+		Expect(gfs.(*FS).Sub(".")).To(BeIdenticalTo(gfs))
+
+		for _, dir := range []string{"/folder", "README", "nada"} {
+			_, err := gfs.(*FS).Sub(dir)
+			var perr *fs.PathError
+			Expect(err).To(BeAssignableToTypeOf(perr))
+			Expect(err.(*fs.PathError).Op).To(Equal("sub"))
+		}
+	})
+
 	DescribeTable("returns an fs.FS for a repository and reference",
 		func(ref string, hascanary bool) {
 			gfs := Successful(NewForRevision(context.Background(), tmprepdir, ref))
@@ -117,3 +168,54 @@ var _ = Describe("git directories", func() {
 	)
 
 })
+
+var _ = Describe("verified git directories", Ordered, func() {
+
+	var signed localremote.SignedRepo
+	var pgpVerifier, sshVerifier *signature.Verifier
+
+	BeforeAll(func() {
+		signed = localremote.CreateTransientSignedRepo()
+		pgpVerifier = Successful(signature.NewOpenPGPVerifier(signed.OpenPGPKeyRing))
+		sshVerifier = Successful(signature.NewSSHVerifier(signed.AllowedSigners))
+	})
+
+	It("accepts signed commits", func(ctx context.Context) {
+		Expect(NewForRevision(ctx, signed.Path, "v0.2.0", remote.WithVerifier(sshVerifier))).
+			Error().NotTo(HaveOccurred())
+		Expect(NewForRevision(ctx, signed.Path, "v0.3.0", remote.WithVerifier(pgpVerifier))).
+			Error().NotTo(HaveOccurred())
+	})
+
+	It("accepts signed annotated tags", func(ctx context.Context) {
+		gfs := Successful(NewForRevision(ctx, signed.Path, "v1.0.0", remote.WithVerifier(pgpVerifier)))
+		Expect(gfs.Revision()).To(Equal("refs/tags/v1.0.0"))
+	})
+
+	It("rejects unsigned and unknown signatures", func(ctx context.Context) {
+		_, err := NewForRevision(ctx, signed.Path, "v0.1.0", remote.WithVerifier(pgpVerifier))
+		var verr *signature.VerificationError
+		Expect(errors.As(err, &verr)).To(BeTrue())
+		Expect(verr.Err).To(MatchError(signature.ErrUnsigned))
+
+		Expect(NewForRevision(ctx, signed.Path, "unsigned-v1.0.0", remote.WithVerifier(pgpVerifier))).
+			Error().To(MatchError(signature.ErrUnsigned))
+		Expect(NewForRevision(ctx, signed.Path, "v0.2.0", remote.WithVerifier(pgpVerifier))).
+			Error().To(HaveOccurred())
+		Expect(NewForRevision(ctx, signed.Path, "v0.3.0", remote.WithVerifier(sshVerifier))).
+			Error().To(HaveOccurred())
+	})
+
+	It("verifies the latest release", func(ctx context.Context) {
+		gfs, latest := Successful2R(OpenLatest(ctx, signed.Path, version.SemverTagMatcher,
+			remote.WithVerifier(pgpVerifier)))
+		Expect(latest.Ref).To(Equal("refs/tags/v1.0.0"))
+		Expect(gfs.Revision()).To(Equal("refs/tags/v1.0.0"))
+
+		Expect(OpenLatest(ctx, signed.Path, version.NewPrefixedTagMatcher("unsigned-"),
+			remote.WithVerifier(pgpVerifier))).Error().To(MatchError(signature.ErrUnsigned))
+		Expect(OpenLatest(ctx, signed.Path, version.SemverTagMatcher,
+			remote.WithVerifier(sshVerifier))).Error().To(MatchError(signature.ErrUnknownKey))
+	})
+
+})
@@ -0,0 +1,184 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/thediveo/gitrepofs/version"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// OpenGoModule determines the latest release of the Go module in the specified
+// subdirectory of the remote repository and returns an [FS] rooted at the
+// module's directory at this release, together with the release tag. Use "" or
+// "." for a module in the repository root directory.
+//
+// OpenGoModule follows the Go module version tag rules, see
+// [version.NewGoModuleTagMatcher]. In particular:
+//   - for a moduleDir with a major version suffix, such as "sub/module/v2",
+//     the returned FS is rooted at the major version subdirectory
+//     "sub/module/v2" if its go.mod declares the "/v2" module path, or
+//     otherwise at "sub/module" if the go.mod there declares the "/v2" module
+//     path.
+//   - for a moduleDir without major version suffix, "+incompatible" versions
+//     are only considered as long as they don't have a go.mod and the latest
+//     v0 or v1 release doesn't have a go.mod either.
+//   - releases are preferred over pre-releases; pre-releases are only
+//     considered when there are no releases at all.
+//
+// [WithExpectedCommit] and [WithExpectedTree] make OpenGoModule fail if the
+// latest release doesn't refer to the expected commit or tree.
+func OpenGoModule(ctx context.Context, remoteURL string, moduleDir string, opts ...Option) (*FS, version.ReleaseTag, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	tags, err := version.ListReleaseTags(ctx, remoteURL,
+		version.NewGoModuleTagMatcher(moduleDir, version.WithPrereleases()), listingOptions(opts)...)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	repo, err := cloneRemote(ctx, remoteURL, "", o)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	tagDir, pathMajor := version.SplitModuleDir(moduleDir)
+	// Multiple tags might refer to the same commit, so create the file system
+	// for each tagged commit only once and only when needed.
+	trees := map[plumbing.Hash]*FS{}
+	treeOf := func(tag version.ReleaseTag) *FS {
+		if gfs, ok := trees[tag.Commit]; ok {
+			return gfs
+		}
+		var gfs *FS
+		if commit, err := repo.CommitObject(tag.Commit); err == nil {
+			gfs, _ = NewForCommit(repo, commit)
+		}
+		trees[tag.Commit] = gfs
+		return gfs
+	}
+	incompatible := true
+	for idx := len(tags) - 1; idx >= 0; idx-- {
+		tag := tags[idx]
+		if strings.HasSuffix(tag.Semver, version.IncompatibleSuffix) ||
+			semver.Prerelease(tag.Semver) != "" {
+			continue
+		}
+		if gfs := treeOf(tag); gfs != nil {
+			_, hasGoMod := gfs.goModulePath(tagDir)
+			incompatible = !hasGoMod
+		}
+		break
+	}
+	for _, prereleases := range []bool{false, true} {
+		for idx := len(tags) - 1; idx >= 0; idx-- {
+			tag := tags[idx]
+			if (semver.Prerelease(tag.Semver) != "") != prereleases ||
+				(!incompatible && strings.HasSuffix(tag.Semver, version.IncompatibleSuffix)) {
+				continue
+			}
+			gfs := treeOf(tag)
+			if gfs == nil {
+				continue
+			}
+			codeDir, ok := gfs.goModuleCodeDir(tagDir, pathMajor, tag.Semver)
+			if !ok {
+				continue
+			}
+			subfs, err := gfs.sub(codeDir)
+			if err != nil {
+				continue
+			}
+			if subfs == gfs {
+				view := *gfs
+				subfs = &view
+			}
+			subfs.revision = tag.Ref
+			if err := checkCommit(o, tag.Commit); err != nil {
+				return nil, version.ReleaseTag{}, fmt.Errorf(
+					"latest release %q of Go module %q in remote repository %q %w",
+					tag.Ref, moduleDir, remoteURL, err)
+			}
+			if err := checkTree(o, gfs.commit); err != nil {
+				return nil, version.ReleaseTag{}, fmt.Errorf(
+					"latest release %q of Go module %q in remote repository %q %w",
+					tag.Ref, moduleDir, remoteURL, err)
+			}
+			if err := o.Verify(repo, tag.Hash); err != nil {
+				return nil, version.ReleaseTag{}, fmt.Errorf(
+					"cannot verify latest release %q of Go module %q in remote repository %q, reason: %w",
+					tag.Ref, moduleDir, remoteURL, err)
+			}
+			return subfs, tag, nil
+		}
+	}
+	return nil, version.ReleaseTag{}, fmt.Errorf(
+		"no release of Go module %q in remote repository %q", moduleDir, remoteURL)
+}
+
+// goModuleCodeDir returns the directory containing the code of the Go module
+// release with the specified tag directory, major version suffix, and version,
+// or false if this isn't a valid release according to the Go module rules.
+func (gfs *FS) goModuleCodeDir(tagDir, pathMajor, semversion string) (string, bool) {
+	if pathMajor != "" {
+		for _, dir := range []string{path.Join(tagDir, pathMajor[1:]), tagDir} {
+			if modpath, ok := gfs.goModulePath(dir); ok && modulePathMajor(modpath) == pathMajor {
+				return fsDir(dir), true
+			}
+		}
+		return "", false
+	}
+	modpath, hasGoMod := gfs.goModulePath(tagDir)
+	if strings.HasSuffix(semversion, version.IncompatibleSuffix) {
+		return fsDir(tagDir), !hasGoMod
+	}
+	if hasGoMod && modulePathMajor(modpath) != "" {
+		return "", false
+	}
+	return fsDir(tagDir), true
+}
+
+// goModulePath returns the module path declared in the go.mod in the
+// specified directory, and false if there is no go.mod.
+func (gfs *FS) goModulePath(dir string) (string, bool) {
+	mod, err := fs.ReadFile(gfs, path.Join(fsDir(dir), "go.mod"))
+	if err != nil {
+		return "", false
+	}
+	return modfile.ModulePath(mod), true
+}
+
+// modulePathMajor returns the major version suffix of the specified module
+// path, such as "/v2", or "" if there is none.
+func modulePathMajor(modpath string) string {
+	_, pathMajor, _ := module.SplitPathVersion(modpath)
+	return pathMajor
+}
+
+// fsDir returns "." for the root directory "", and otherwise dir unchanged.
+func fsDir(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("Go modules", Ordered, func() {
+
+	var monorepodir string
+
+	BeforeAll(func() {
+		monorepodir = localremote.CreateTransientModuleRepo()
+	})
+
+	DescribeTable("opens the latest release of a Go module",
+		func(ctx context.Context, moduleDir string, expsemver string, expref string, file string, gomod string) {
+			gfs, tag := Successful2R(OpenGoModule(ctx, monorepodir, moduleDir))
+			Expect(tag.Semver).To(Equal(expsemver))
+			Expect(tag.Ref).To(Equal(expref))
+			Expect(fs.ReadFile(gfs, file)).NotTo(BeEmpty())
+			if gomod == "" {
+				Expect(fs.ReadFile(gfs, "go.mod")).Error().To(HaveOccurred())
+				return
+			}
+			Expect(fs.ReadFile(gfs, "go.mod")).To(ContainSubstring("module " + gomod + "\n"))
+		},
+		Entry("root module", "", "v0.1.0", "refs/tags/v0.1.0", "README", ""),
+		Entry("subdirectory module", "sub/module", "v1.1.0", "refs/tags/sub/module/v1.1.0",
+			"module.go", "example.org/mono/sub/module"),
+		Entry("major version subdirectory", "sub/module/v2", "v2.1.0", "refs/tags/sub/module/v2.1.0",
+			"module.go", "example.org/mono/sub/module/v2"),
+		Entry("major branch", "sub/module/v3", "v3.0.0", "refs/tags/sub/module/v3.0.0",
+			"v2/module.go", "example.org/mono/sub/module/v3"),
+		Entry("incompatible", "other", "v3.0.0+incompatible", "refs/tags/other/v3.0.0",
+			"other.go", ""),
+	)
+
+	It("reports missing modules and mismatching commits", func(ctx context.Context) {
+		Expect(OpenGoModule(ctx, monorepodir, "nada")).Error().To(HaveOccurred())
+		Expect(OpenGoModule(ctx, monorepodir, "sub/module/v4")).Error().To(HaveOccurred())
+		Expect(OpenGoModule(ctx, monorepodir, "sub/module",
+			WithExpectedCommit(commit.Hash.String()))).Error().To(MatchError(ErrCommitMismatch))
+	})
+
+})
@@ -0,0 +1,260 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// binarySniffLen is the number of leading bytes checked for NUL bytes when
+// detecting binary files, the same as git uses.
+const binarySniffLen = 8000
+
+// GrepOptions control how [Grep] searches the files of an [FS].
+type GrepOptions struct {
+	// Path of the directory to search, defaults to the root directory.
+	Path string
+	// Include lists the glob patterns of the files to search, as understood
+	// by [path.Match]. Patterns without any “/” match the base names of
+	// files, otherwise the paths relative to the root of the file system.
+	// If empty, all files get searched.
+	Include []string
+	// Exclude lists the glob patterns of the files and directories not to
+	// search, in the same format as Include. Excluded directories are
+	// skipped as a whole.
+	Exclude []string
+	// Binary searches binary files too, instead of skipping them. Files are
+	// considered to be binary when they contain a NUL byte within their first
+	// 8000 bytes, as git does.
+	Binary bool
+	// Workers is the number of files searched in parallel, defaults to the
+	// number of usable CPUs.
+	Workers int
+}
+
+// GrepMatch is a single match found by [Grep].
+type GrepMatch struct {
+	// Path of the file relative to the root of the file system.
+	Path string
+	// Line number of the match, starting with 1.
+	Line int
+	// Column of the start of the match, in bytes starting with 1.
+	Column int
+	// Text of the line containing the match, without the line ending.
+	Text string
+}
+
+// lineMatch is a match found in some file contents.
+type lineMatch struct {
+	line   int
+	column int
+	text   string
+}
+
+// Grep searches the regular and executable files of the file system for the
+// regular expression pattern, as understood by [regexp.Compile], returning
+// all matches sorted by path, line, and column. Patterns are matched against
+// individual lines, so patterns never match across line endings. Grep reads
+// the files as served by the file system, taking text conversion and archive
+// views into account.
+//
+// Files with identical contents get searched only once, even if they appear
+// multiple times in the file system.
+func Grep(ctx context.Context, gfs *FS, pattern string, opts *GrepOptions) ([]GrepMatch, error) {
+	if opts == nil {
+		opts = &GrepOptions{}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep pattern, reason: %w", err)
+	}
+	dir := cmp.Or(opts.Path, ".")
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "grep", Path: dir, Err: fs.ErrInvalid}
+	}
+	for _, glob := range slices.Concat(opts.Include, opts.Exclude) {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid grep glob %q, reason: %w", glob, err)
+		}
+	}
+
+	// Group the files to search by their contents, so that files with the
+	// same contents get searched only once. As converted contents depend on
+	// the git attributes of each individual file, these are never shared.
+	type job struct {
+		name  string
+		entry object.TreeEntry
+		names []string
+	}
+	jobs := map[string]*job{}
+	order := []string{}
+	err = gfs.walkTree(dir, func(name string, entry object.TreeEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if matchesGlob(opts.Exclude, name) {
+			if entry.Mode == filemode.Dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Mode != filemode.Regular && entry.Mode != filemode.Executable {
+			return nil
+		}
+		if len(opts.Include) > 0 && !matchesGlob(opts.Include, name) {
+			return nil
+		}
+		key := entry.Hash.String()
+		if gfs.converts() {
+			key += ":" + name
+		}
+		if j, ok := jobs[key]; ok {
+			j.names = append(j.names, name)
+			return nil
+		}
+		jobs[key] = &job{name: name, entry: entry, names: []string{name}}
+		order = append(order, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot grep %q, reason: %w", dir, err)
+	}
+
+	keys := make(chan string)
+	var mu sync.Mutex
+	results := map[string][]lineMatch{}
+	var firstErr error
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for key := range keys {
+				j := jobs[key]
+				matches, err := grepFile(ctx, gfs, j.name, j.entry, re, opts.Binary)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				results[key] = matches
+				mu.Unlock()
+			}
+		})
+	}
+	for _, key := range order {
+		if ctx.Err() != nil {
+			break
+		}
+		keys <- key
+	}
+	close(keys)
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		return nil, fmt.Errorf("cannot grep %q, reason: %w", dir, firstErr)
+	}
+
+	grepMatches := []GrepMatch{}
+	for key, matches := range results {
+		for _, name := range jobs[key].names {
+			for _, m := range matches {
+				grepMatches = append(grepMatches, GrepMatch{
+					Path:   name,
+					Line:   m.line,
+					Column: m.column,
+					Text:   m.text,
+				})
+			}
+		}
+	}
+	slices.SortFunc(grepMatches, func(a, b GrepMatch) int {
+		return cmp.Or(
+			strings.Compare(a.Path, b.Path),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column))
+	})
+	return grepMatches, nil
+}
+
+// grepFile returns the matches of the regular expression in the contents of
+// the named file. Binary files are skipped unless binary is true.
+func grepFile(ctx context.Context, gfs *FS, name string, entry object.TreeEntry, re *regexp.Regexp, binary bool) ([]lineMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r, _, err := gfs.openBlob(name, entry)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !binary && bytes.IndexByte(contents[:min(len(contents), binarySniffLen)], 0) >= 0 {
+		return nil, nil
+	}
+	// like “git grep”, empty files have no lines at all, not a single empty
+	// line.
+	if len(contents) == 0 {
+		return nil, nil
+	}
+	var matches []lineMatch
+	contents = bytes.TrimSuffix(contents, []byte("\n"))
+	for lineno, line := range bytes.Split(contents, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		for _, loc := range re.FindAllIndex(line, -1) {
+			matches = append(matches, lineMatch{
+				line:   lineno + 1,
+				column: loc[0] + 1,
+				text:   string(line),
+			})
+		}
+	}
+	return matches, nil
+}
+
+// matchesGlob returns true if any of the glob patterns matches the name. Glob
+// patterns without any “/” match the base name, otherwise the full name.
+func matchesGlob(globs []string, name string) bool {
+	for _, glob := range globs {
+		subject := name
+		if !strings.Contains(glob, "/") {
+			subject = path.Base(name)
+		}
+		if ok, _ := path.Match(glob, subject); ok {
+			return true
+		}
+	}
+	return false
+}
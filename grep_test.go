@@ -0,0 +1,106 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("grepping", Ordered, func() {
+
+	var gfs *FS
+
+	BeforeAll(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "HEAD"))
+	})
+
+	paths := func(matches []GrepMatch) []string {
+		paths := []string{}
+		for _, m := range matches {
+			paths = append(paths, m.Path)
+		}
+		return paths
+	}
+
+	It("finds matches with their positions", func(ctx context.Context) {
+		matches := Successful(Grep(ctx, gfs, `ch(i)rp`, nil))
+		Expect(matches).To(HaveExactElements(
+			GrepMatch{Path: "folder/subfolder/canary.txt", Line: 1, Column: 1, Text: "chirp!"},
+			GrepMatch{Path: "folder/subfolder/canary.txt", Line: 2, Column: 1, Text: "chirp!"},
+		))
+
+		matches = Successful(Grep(ctx, gfs, `line`, &GrepOptions{Path: "text", Workers: 1}))
+		Expect(matches).To(ContainElements(
+			GrepMatch{Path: "text/auto.txt", Line: 1, Column: 7, Text: "first line"},
+			GrepMatch{Path: "text/auto.txt", Line: 2, Column: 8, Text: "second line"},
+		))
+		Expect(paths(matches)).To(HaveEach(HavePrefix("text/")))
+	})
+
+	It("filters paths", func(ctx context.Context) {
+		Expect(paths(Successful(Grep(ctx, gfs, `.`, &GrepOptions{
+			Include: []string{"*.txt"},
+			Exclude: []string{"text"},
+		})))).To(HaveEach("folder/subfolder/canary.txt"))
+		Expect(paths(Successful(Grep(ctx, gfs, `.`, &GrepOptions{
+			Include: []string{"folder/*/*.sh"},
+		})))).To(HaveEach("folder/subfolder/schkript.sh"))
+		Expect(paths(Successful(Grep(ctx, gfs, `.`, &GrepOptions{
+			Exclude: []string{"text/*.txt", "folder"},
+		})))).To(ContainElements("README", "text/blob.dat", "text/.gitattributes"))
+	})
+
+	It("skips binary files unless asked to", func(ctx context.Context) {
+		// the UTF-8 text gets converted into UTF-16 with NUL bytes.
+		textfs := gfs.WithTextConversion()
+		Expect(paths(Successful(Grep(ctx, gfs, `Gr`, &GrepOptions{Path: "text"})))).To(
+			ContainElement("text/utf16.txt"))
+		Expect(paths(Successful(Grep(ctx, textfs, `.`, &GrepOptions{Path: "text"})))).NotTo(
+			ContainElement("text/utf16.txt"))
+		Expect(paths(Successful(Grep(ctx, textfs, `G`, &GrepOptions{Path: "text", Binary: true})))).To(
+			ContainElement("text/utf16.txt"))
+	})
+
+	It("doesn't match empty files", func(ctx context.Context) {
+		Expect(fs.ReadFile(gfs, "fodder/empty")).To(BeEmpty())
+		Expect(Grep(ctx, gfs, `^$`, &GrepOptions{Path: "fodder"})).To(BeEmpty())
+	})
+
+	It("reports all files with the same contents", func(ctx context.Context) {
+		modfs := Successful(NewForRevision(ctx, localremote.CreateTransientModuleRepo(), "HEAD"))
+		Expect(paths(Successful(Grep(ctx, modfs, `^package module$`, nil)))).To(HaveExactElements(
+			"sub/module/module.go", "sub/module/v2/module.go"))
+	})
+
+	It("reports errors", func(ctx context.Context) {
+		Expect(Grep(ctx, gfs, `(`, nil)).Error().To(MatchError(ContainSubstring("invalid grep pattern")))
+		Expect(Grep(ctx, gfs, `.`, &GrepOptions{Path: "/"})).Error().To(MatchError(fs.ErrInvalid))
+		Expect(Grep(ctx, gfs, `.`, &GrepOptions{Path: "nada"})).Error().To(MatchError(fs.ErrNotExist))
+		Expect(Grep(ctx, gfs, `.`, &GrepOptions{Include: []string{"["}})).Error().To(
+			MatchError(ContainSubstring("invalid grep glob")))
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(Grep(ctx, gfs, `.`, nil)).Error().To(MatchError(context.Canceled))
+	})
+
+})
@@ -0,0 +1,201 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// indexPage is the name of the file served instead of a directory listing.
+const indexPage = "index.html"
+
+var _ http.Handler = (*Handler)(nil)
+
+// Handler serves the files and directories of an [FS] over HTTP, similar to
+// [http.FileServer], but with caching headers derived from git:
+//   - the ETag of a file is its blob hash, and the ETag of a directory is its
+//     tree hash. When the file system applies text conversion, the ETag
+//     additionally contains the hash of the root tree, as the served contents
+//     then also depend on the git attributes. In archive views, the ETag
+//     instead contains the commit hash, as “export-subst” additionally
+//     expands commit information.
+//   - Last-Modified is the modification time of the files and directories
+//     as reported by [FileInfo.ModTime].
+//
+// Handler supports range requests, conditional requests using If-None-Match
+// and If-Modified-Since, as well as content type detection, as implemented by
+// [http.ServeContent]. Directories are served as their “index.html” if
+// present, or otherwise as HTML listings. Symbolic links and submodules are
+// not served.
+type Handler struct {
+	gfs *FS
+}
+
+// NewHandler returns a new [Handler] serving the specified file system.
+func NewHandler(gfs *FS) *Handler {
+	return &Handler{gfs: gfs}
+}
+
+// ServeHTTP serves the file or directory named by the request's URL path,
+// replying to requests other than GET and HEAD with “405 Method Not Allowed”.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	upath := r.URL.Path
+	if !strings.HasPrefix(upath, "/") {
+		upath = "/" + upath
+	}
+	name := strings.TrimPrefix(path.Clean(upath), "/")
+	if name == "" {
+		name = "."
+	}
+	hash, isDir, err := h.gfs.entryHash(name)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	if isDir {
+		if !strings.HasSuffix(upath, "/") {
+			redirect(w, r, path.Base(upath)+"/")
+			return
+		}
+		if indexHash, isIndexDir, err := h.gfs.entryHash(path.Join(name, indexPage)); err == nil && !isIndexDir {
+			h.serveFile(w, r, path.Join(name, indexPage), indexHash)
+			return
+		}
+		h.serveDir(w, r, name, hash)
+		return
+	}
+	if strings.HasSuffix(upath, "/") {
+		redirect(w, r, "../"+path.Base(upath))
+		return
+	}
+	h.serveFile(w, r, name, hash)
+}
+
+// serveFile serves the contents of the named file.
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, name string, hash plumbing.Hash) {
+	f, err := h.gfs.Open(name)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	contents, err := io.ReadAll(f)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	w.Header().Set("ETag", h.etag(hash))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), bytes.NewReader(contents))
+}
+
+// serveDir serves an HTML listing of the named directory.
+func (h *Handler) serveDir(w http.ResponseWriter, r *http.Request, name string, hash plumbing.Hash) {
+	entries, err := fs.ReadDir(h.gfs, name)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	var listing bytes.Buffer
+	listing.WriteString("<!doctype html>\n<meta name=\"viewport\" content=\"width=device-width\">\n<pre>\n")
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		// the name may contain ':', so make sure it doesn't get mistaken for a
+		// URL scheme.
+		link := url.URL{Path: "./" + entryName}
+		fmt.Fprintf(&listing, "<a href=\"%s\">%s</a>\n",
+			html.EscapeString(link.String()), html.EscapeString(entryName))
+	}
+	listing.WriteString("</pre>\n")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("ETag", h.etag(hash))
+	http.ServeContent(w, r, "", h.gfs.mtime, bytes.NewReader(listing.Bytes()))
+}
+
+// etag returns the quoted entity tag for the blob or tree with the specified
+// hash. As “export-subst” expands commit information in archive views, the
+// entity tag then contains the commit hash instead of the root tree hash.
+func (h *Handler) etag(hash plumbing.Hash) string {
+	if commit := h.gfs.CommitHash(); h.gfs.archive && !commit.IsZero() {
+		return fmt.Sprintf("%q", hash.String()+"-"+commit.String())
+	}
+	if h.gfs.converts() {
+		return fmt.Sprintf("%q", hash.String()+"-"+h.gfs.tree.Hash.String())
+	}
+	return fmt.Sprintf("%q", hash.String())
+}
+
+// entryHash returns the hash of the named file or directory, and true if it is
+// a directory.
+func (gfs *FS) entryHash(name string) (plumbing.Hash, bool, error) {
+	if name == "." {
+		return gfs.tree.Hash, true, nil
+	}
+	info, err := fs.Stat(gfs, name)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	entry, err := gfs.tree.FindEntry(name)
+	if err != nil {
+		return plumbing.ZeroHash, false, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return entry.Hash, info.IsDir(), nil
+}
+
+// redirect replies with a redirect to the specified relative location,
+// preserving any query.
+func redirect(w http.ResponseWriter, r *http.Request, location string) {
+	if q := r.URL.RawQuery; q != "" {
+		location += "?" + q
+	}
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+// httpError replies with the HTTP status corresponding to the error, without
+// leaking any details of the error to the client.
+func httpError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+		http.Error(w, "404 page not found", http.StatusNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+	default:
+		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+	}
+}
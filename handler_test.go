@@ -0,0 +1,148 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("serving over HTTP", func() {
+
+	var gfs *FS
+	var handler *Handler
+
+	BeforeEach(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, commit.Hash.String()))
+		handler = NewHandler(gfs)
+	})
+
+	serve := func(method, target string, headers ...string) *httptest.ResponseRecorder {
+		GinkgoHelper()
+		req := httptest.NewRequest(method, target, nil)
+		for idx := 0; idx+1 < len(headers); idx += 2 {
+			req.Header.Set(headers[idx], headers[idx+1])
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("serves files with git-aware caching headers", func() {
+		entry := Successful(gfs.tree.FindEntry("folder/subfolder/canary.txt"))
+		etag := fmt.Sprintf("%q", entry.Hash.String())
+
+		rec := serve(http.MethodGet, "/folder/subfolder/canary.txt")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("chirp!"))
+		Expect(rec.Header().Get("ETag")).To(Equal(etag))
+		Expect(rec.Header().Get("Last-Modified")).To(
+			Equal(commit.Author.When.UTC().Format(http.TimeFormat)))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+
+		rec = serve(http.MethodGet, "/folder/subfolder/canary.txt", "If-None-Match", etag)
+		Expect(rec.Code).To(Equal(http.StatusNotModified))
+		Expect(rec.Body.Len()).To(BeZero())
+
+		rec = serve(http.MethodGet, "/folder/subfolder/canary.txt", "If-None-Match", `"0000"`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = serve(http.MethodHead, "/folder/subfolder/canary.txt")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.Len()).To(BeZero())
+	})
+
+	It("serves ranges", func() {
+		contents := Successful(fs.ReadFile(gfs, "README"))
+		Expect(len(contents)).To(BeNumerically(">", 4))
+
+		rec := serve(http.MethodGet, "/README", "Range", "bytes=1-3")
+		Expect(rec.Code).To(Equal(http.StatusPartialContent))
+		Expect(rec.Body.Bytes()).To(Equal(contents[1:4]))
+		Expect(rec.Header().Get("Content-Range")).To(
+			Equal(fmt.Sprintf("bytes 1-3/%d", len(contents))))
+	})
+
+	It("serves directory listings", func() {
+		rec := serve(http.MethodGet, "/")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(rec.Header().Get("ETag")).To(Equal(fmt.Sprintf("%q", gfs.tree.Hash.String())))
+		Expect(rec.Body.String()).To(And(
+			ContainSubstring(`<a href="./README">README</a>`),
+			ContainSubstring(`<a href="./folder/">folder/</a>`)))
+
+		rec = serve(http.MethodGet, "/folder")
+		Expect(rec.Code).To(Equal(http.StatusMovedPermanently))
+		Expect(rec.Header().Get("Location")).To(Equal("folder/"))
+
+		rec = serve(http.MethodGet, "/README/")
+		Expect(rec.Code).To(Equal(http.StatusMovedPermanently))
+		Expect(rec.Header().Get("Location")).To(Equal("../README"))
+
+		rec = serve(http.MethodGet, "/folder/subfolder/")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`<a href="./canary.txt">canary.txt</a>`))
+	})
+
+	It("includes the root tree in the ETag of converted contents", func() {
+		handler = NewHandler(gfs.WithTextConversion())
+		entry := Successful(gfs.tree.FindEntry("README"))
+		rec := serve(http.MethodGet, "/README")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("ETag")).To(Equal(
+			fmt.Sprintf("%q", entry.Hash.String()+"-"+gfs.tree.Hash.String())))
+	})
+
+	It("includes the commit in the ETag of archive views", func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "master"))
+		recommit := *gfs.commit
+		recommit.Message = "same tree, different commit\n"
+		obj := gfs.repo.Storer.NewEncodedObject()
+		Expect(recommit.Encode(obj)).To(Succeed())
+		other := Successful(gfs.repo.CommitObject(Successful(gfs.repo.Storer.SetEncodedObject(obj))))
+		Expect(other.TreeHash).To(Equal(gfs.commit.TreeHash))
+		Expect(other.Hash).NotTo(Equal(gfs.commit.Hash))
+
+		handler = NewHandler(gfs.WithArchiveView())
+		rec := serve(http.MethodGet, "/VERSION")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		etag := rec.Header().Get("ETag")
+		Expect(etag).To(HaveSuffix(gfs.commit.Hash.String() + `"`))
+
+		handler = NewHandler(Successful(NewForCommit(gfs.repo, other)).WithArchiveView())
+		rec = serve(http.MethodGet, "/VERSION", "If-None-Match", etag)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("ETag")).NotTo(Equal(etag))
+		Expect(rec.Body.String()).To(ContainSubstring("commit: " + other.Hash.String()))
+	})
+
+	It("reports errors", func() {
+		Expect(serve(http.MethodGet, "/nada").Code).To(Equal(http.StatusNotFound))
+		Expect(serve(http.MethodGet, "/folder/nada/").Code).To(Equal(http.StatusNotFound))
+
+		rec := serve(http.MethodPost, "/README")
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(rec.Header().Get("Allow")).To(Equal("GET, HEAD"))
+	})
+
+})
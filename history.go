@@ -0,0 +1,299 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Top-level directories of a [HistoryFS].
+const (
+	TagsDir     = "tags"
+	BranchesDir = "branches"
+	CommitsDir  = "commits"
+)
+
+var _ fs.FS = (*HistoryFS)(nil)
+
+// HistoryFS provides a view onto multiple revisions of the same repository,
+// presenting them as virtual directories:
+//   - tags/<name>/... for the tags, where tag names with slashes result in
+//     further virtual directory levels, such as tags/sub/module/v1.2.3/...
+//   - branches/<name>/... for the branches, such as branches/master/...
+//   - commits/<hash>/... for the commits, using full commit hashes.
+//
+// Each revision is backed by an [FS] that gets constructed lazily only when
+// accessing the revision for the first time; revisions referring to the same
+// commit share the same FS.
+type HistoryFS struct {
+	repo *git.Repository
+
+	mu  sync.Mutex
+	fss map[plumbing.Hash]*FS // commit hash -> FS
+}
+
+// NewHistory clones the specified remote repository into memory with all its
+// tags and branches, and returns a [HistoryFS] for it.
+// As a HistoryFS doesn't resolve a particular revision, NewHistory rejects
+// [WithExpectedCommit] and [WithExpectedTree], as well as
+// [remote.WithVerifier].
+func NewHistory(ctx context.Context, remoteURL string, opts ...Option) (*HistoryFS, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.RejectPins(); err != nil {
+		return nil, err
+	}
+	if err := o.RejectVerifier(); err != nil {
+		return nil, err
+	}
+	cloneOpts := o.CloneOptions(remoteURL)
+	cloneOpts.Mirror = true
+	repo, err := clone(ctx, cloneOpts, o)
+	if err != nil {
+		return nil, err
+	}
+	return NewHistoryForRepository(repo), nil
+}
+
+// NewHistoryForRepository returns a [HistoryFS] for the specified repository,
+// with its local branches (refs/heads/...) as the branches.
+func NewHistoryForRepository(repo *git.Repository) *HistoryFS {
+	return &HistoryFS{
+		repo: repo,
+		fss:  map[plumbing.Hash]*FS{},
+	}
+}
+
+// Open opens the named file or directory, following the rules of
+// [fs.FS.Open]. When Open returns an error, it is of type [*fs.PathError] with
+// the Op field set to "open", the Path field set to name, and the Err field
+// describing the problem.
+func (h *HistoryFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return newVirtualDir(".", []string{BranchesDir, CommitsDir, TagsDir}), nil
+	}
+	top, rest, _ := strings.Cut(name, "/")
+	var names []string
+	switch top {
+	case TagsDir:
+		names = h.refNames("refs/tags/")
+	case BranchesDir:
+		names = h.refNames("refs/heads/")
+	case CommitsDir:
+		if rest == "" {
+			names = h.commitNames()
+			break
+		}
+		// avoid iterating over all commits when we already know the commit.
+		hash, _, _ := strings.Cut(rest, "/")
+		if plumbing.IsHash(hash) {
+			names = []string{hash}
+		}
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if rest == "" {
+		return newVirtualDir(top, children(names, "")), nil
+	}
+	for _, revname := range names {
+		if rest != revname && !strings.HasPrefix(rest, revname+"/") {
+			continue
+		}
+		gfs, err := h.revisionFS(top, revname)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return openInRevision(gfs, name, path.Base(revname), strings.TrimPrefix(rest[len(revname):], "/"))
+	}
+	if entries := children(names, rest); len(entries) > 0 {
+		return newVirtualDir(path.Base(rest), entries), nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// openInRevision opens the named file or directory inside the file system of
+// a revision, reporting errors for the full name. The revision's root
+// directory gets named after the revision's directory.
+func openInRevision(gfs *FS, fullname string, revbase string, name string) (fs.File, error) {
+	if name == "" {
+		name = "."
+	}
+	f, err := gfs.Open(name)
+	if err != nil {
+		var perr *fs.PathError
+		if errors.As(err, &perr) {
+			perr.Path = fullname
+		}
+		return nil, err
+	}
+	if d, ok := f.(*Directory); ok && name == "." {
+		d.fileinfo = NewFileInfoFromTree(gfs.tree, revbase, gfs.mtime)
+	}
+	return f, nil
+}
+
+// revisionFS returns the (cached) file system for the named revision in the
+// specified top-level directory.
+func (h *HistoryFS) revisionFS(top string, revname string) (*FS, error) {
+	var commitHash plumbing.Hash
+	switch top {
+	case CommitsDir:
+		commitHash = plumbing.NewHash(revname)
+	case TagsDir, BranchesDir:
+		refname := "refs/tags/" + revname
+		if top == BranchesDir {
+			refname = "refs/heads/" + revname
+		}
+		hash, err := h.repo.ResolveRevision(plumbing.Revision(refname))
+		if err != nil {
+			return nil, fs.ErrNotExist
+		}
+		commitHash = *hash
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if gfs, ok := h.fss[commitHash]; ok {
+		return gfs, nil
+	}
+	commit, err := h.repo.CommitObject(commitHash)
+	if err != nil {
+		return nil, fs.ErrNotExist
+	}
+	gfs, err := NewForCommit(h.repo, commit)
+	if err != nil {
+		return nil, fs.ErrNotExist
+	}
+	h.fss[commitHash] = gfs
+	return gfs, nil
+}
+
+// refNames returns the names of the references with the specified prefix,
+// with the prefix removed.
+func (h *HistoryFS) refNames(prefix string) []string {
+	names := []string{}
+	refs, err := h.repo.References()
+	if err != nil {
+		return names
+	}
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		if name, ok := strings.CutPrefix(ref.Name().String(), prefix); ok {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names
+}
+
+// commitNames returns the hashes of all commits in the repository.
+func (h *HistoryFS) commitNames() []string {
+	names := []string{}
+	commits, err := h.repo.CommitObjects()
+	if err != nil {
+		return names
+	}
+	_ = commits.ForEach(func(commit *object.Commit) error {
+		names = append(names, commit.Hash.String())
+		return nil
+	})
+	return names
+}
+
+// children returns the sorted unique next path elements of the names below
+// the specified directory, where "" is the top-level directory.
+func children(names []string, dir string) []string {
+	if dir != "" {
+		dir += "/"
+	}
+	elements := []string{}
+	for _, name := range names {
+		rest, ok := strings.CutPrefix(name, dir)
+		if !ok {
+			continue
+		}
+		element, _, _ := strings.Cut(rest, "/")
+		elements = append(elements, element)
+	}
+	slices.Sort(elements)
+	return slices.Compact(elements)
+}
+
+// virtualDir is a directory with a fixed list of entries, such as the
+// top-level directories of a [HistoryFS] or the merged directories of an
+// [Overlay].
+type virtualDir struct {
+	fileinfo fs.FileInfo
+	entries  []fs.DirEntry
+	index    int
+}
+
+var _ fs.ReadDirFile = (*virtualDir)(nil)
+
+// newVirtualDir returns a new virtual directory with the specified name and
+// subdirectories.
+func newVirtualDir(name string, subdirs []string) *virtualDir {
+	entries := make([]fs.DirEntry, 0, len(subdirs))
+	for _, subdir := range subdirs {
+		entries = append(entries, NewDirEntry(
+			object.TreeEntry{Name: subdir, Mode: filemode.Dir}, 0, time.Time{}))
+	}
+	return &virtualDir{
+		fileinfo: NewFileInfo(object.TreeEntry{Name: name, Mode: filemode.Dir}, 0, time.Time{}),
+		entries:  entries,
+	}
+}
+
+// Stat returns information about this virtual directory.
+func (d *virtualDir) Stat() (fs.FileInfo, error) { return d.fileinfo, nil }
+
+// ReadDir reads the entries of this virtual directory.
+func (d *virtualDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.index < 0 {
+		return nil, errors.New("closed directory")
+	}
+	count := len(d.entries) - d.index
+	if n > 0 {
+		if count == 0 {
+			return nil, io.EOF
+		}
+		count = min(count, n)
+	}
+	direntries := slices.Clone(d.entries[d.index : d.index+count])
+	d.index += count
+	return direntries, nil
+}
+
+// Read nothing from this virtual directory.
+func (d *virtualDir) Read(b []byte) (int, error) { return 0, io.EOF }
+
+// Close this virtual directory.
+func (d *virtualDir) Close() error { d.index = -1; return nil }
@@ -0,0 +1,144 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/thediveo/gitrepofs/remote"
+	"github.com/thediveo/gitrepofs/signature"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("history file systems", Ordered, func() {
+
+	var h *HistoryFS
+
+	BeforeAll(func(ctx context.Context) {
+		h = Successful(NewHistory(ctx, tmprepdir))
+	})
+
+	names := func(direntries []fs.DirEntry) []string {
+		names := []string{}
+		for _, direntry := range direntries {
+			Expect(direntry.IsDir()).To(BeTrue())
+			names = append(names, direntry.Name())
+		}
+		return names
+	}
+
+	It("lists tags, branches, and commits", func() {
+		Expect(names(Successful(fs.ReadDir(h, ".")))).To(
+			HaveExactElements(BranchesDir, CommitsDir, TagsDir))
+		Expect(names(Successful(fs.ReadDir(h, TagsDir)))).To(
+			ContainElements("v1.0", "v1.0.1", "v1.1.1", "v2.0.0-rc1", "build-10"))
+		Expect(names(Successful(fs.ReadDir(h, BranchesDir)))).To(
+			HaveExactElements("master", "release-1.0", "release-1.1", "stable"))
+		Expect(names(Successful(fs.ReadDir(h, "branches/stable")))).To(
+			HaveExactElements("1.x", "2.x"))
+		Expect(names(Successful(fs.ReadDir(h, CommitsDir)))).To(
+			ContainElement(commit.Hash.String()))
+	})
+
+	It("reads files from revisions", func() {
+		Expect(fs.ReadFile(h, "tags/v1.1.1/folder/subfolder/canary.txt")).To(ContainSubstring("chirp!"))
+		Expect(fs.ReadFile(h, "branches/stable/1.x/folder/subfolder/canary.txt")).To(ContainSubstring("chirp!"))
+		Expect(fs.ReadFile(h, "commits/"+commit.Hash.String()+"/README")).NotTo(BeEmpty())
+		Expect(names(Successful(fs.ReadDir(h, "tags/v1.1.1/folder")))).To(HaveExactElements("subfolder"))
+
+		fi := Successful(fs.Stat(h, "tags/v1.1.1"))
+		Expect(fi.Name()).To(Equal("v1.1.1"))
+		Expect(fi.IsDir()).To(BeTrue())
+		fi = Successful(fs.Stat(h, "branches/stable/2.x"))
+		Expect(fi.Name()).To(Equal("2.x"))
+		fi = Successful(fs.Stat(h, "branches/stable"))
+		Expect(fi.Name()).To(Equal("stable"))
+		Expect(fi.IsDir()).To(BeTrue())
+
+		var files []string
+		Expect(fs.WalkDir(h, "branches/release-1.1", func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				files = append(files, path)
+			}
+			return err
+		})).To(Succeed())
+		Expect(files).To(ConsistOf(
+			"branches/release-1.1/README",
+			"branches/release-1.1/fodder/empty",
+			"branches/release-1.1/folder/subfolder/canary.txt",
+			"branches/release-1.1/folder/subfolder/schkript.sh",
+		))
+	})
+
+	It("lazily shares file systems between revisions of the same commit", func() {
+		history := NewHistoryForRepository(h.repo)
+		Expect(history.fss).To(BeEmpty())
+		Expect(fs.ReadFile(history, "tags/v1.0/README")).NotTo(BeEmpty())
+		Expect(fs.ReadFile(history, "tags/v1.0.1/README")).NotTo(BeEmpty())
+		Expect(fs.ReadFile(history, "branches/release-1.0/README")).NotTo(BeEmpty())
+		Expect(history.fss).To(HaveLen(1))
+	})
+
+	DescribeTable("reports errors",
+		func(name string, experr error) {
+			_, err := h.Open(name)
+			var perr *fs.PathError
+			Expect(err).To(BeAssignableToTypeOf(perr))
+			perr = err.(*fs.PathError)
+			Expect(perr.Op).To(Equal("open"))
+			Expect(perr.Path).To(Equal(name))
+			Expect(perr.Err).To(Equal(experr))
+		},
+		Entry(nil, "/tags", fs.ErrInvalid),
+		Entry(nil, "nada", fs.ErrNotExist),
+		Entry(nil, "tags/nada", fs.ErrNotExist),
+		Entry(nil, "tags/v1.0/folder", fs.ErrNotExist),
+		Entry(nil, "branches/stable/3.x", fs.ErrNotExist),
+		Entry(nil, "commits/nada", fs.ErrNotExist),
+		Entry(nil, "commits/0123456789012345678901234567890123456789", fs.ErrNotExist),
+	)
+
+	It("reads virtual directories in chunks", func() {
+		d := Successful(h.Open("branches")).(fs.ReadDirFile)
+		Expect(d.Read(nil)).Error().To(MatchError(io.EOF))
+		Expect(d.ReadDir(3)).To(HaveLen(3))
+		Expect(d.ReadDir(3)).To(HaveLen(1))
+		Expect(d.ReadDir(3)).Error().To(MatchError(io.EOF))
+		Expect(d.ReadDir(-1)).To(BeEmpty())
+		Expect(d.Close()).To(Succeed())
+		Expect(d.ReadDir(-1)).Error().To(HaveOccurred())
+	})
+
+	It("reports inaccessible remotes", func(ctx context.Context) {
+		Expect(NewHistory(ctx, "/nada")).Error().To(HaveOccurred())
+	})
+
+	It("rejects pinning options", func(ctx context.Context) {
+		Expect(NewHistory(ctx, tmprepdir, WithExpectedCommit(commit.Hash.String()))).
+			Error().To(MatchError(remote.ErrUnsupportedOption))
+	})
+
+	It("rejects verifiers", func(ctx context.Context) {
+		verifier := Successful(signature.NewSSHVerifier(nil))
+		Expect(NewHistory(ctx, tmprepdir, remote.WithVerifier(verifier))).
+			Error().To(MatchError(remote.ErrUnsupportedOption))
+	})
+
+})
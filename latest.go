@@ -0,0 +1,80 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/thediveo/gitrepofs/version"
+)
+
+// OpenLatest determines the latest release in the specified remote repository
+// according to the specified version scheme, such as
+// [version.SemverTagMatcher], and returns an [FS] for it, together with the
+// release's version, reference name, and commit hash.
+//
+// In contrast to calling [version.LatestReleaseTag] followed by
+// [NewForRevision], OpenLatest lists the remote references only once and then
+// fetches exactly the listed commit. If the reference has been moved in the
+// meantime so that the listed commit isn't available anymore, OpenLatest
+// fails instead of silently returning a different commit.
+//
+// [WithExpectedCommit] and [WithExpectedTree] make OpenLatest fail if the
+// latest release doesn't refer to the expected commit or tree. Similarly,
+// [remote.WithVerifier] makes OpenLatest fail if the latest release's
+// annotated tag, or otherwise its commit, doesn't carry a trusted signature.
+func OpenLatest(ctx context.Context, remoteURL string, scheme version.Scheme, opts ...Option) (*FS, version.ReleaseTag, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	latest, err := version.LatestReleaseForScheme(ctx, remoteURL, scheme, listingOptions(opts)...)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	if err := checkCommit(o, latest.Commit); err != nil {
+		return nil, version.ReleaseTag{}, fmt.Errorf(
+			"latest release %q in remote repository %q %w",
+			latest.Ref, remoteURL, err)
+	}
+	repo, err := cloneRemote(ctx, remoteURL, plumbing.ReferenceName(latest.Ref), o)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	commit, err := repo.CommitObject(latest.Commit)
+	if err != nil {
+		return nil, version.ReleaseTag{}, fmt.Errorf(
+			"commit %s of latest release %q not available anymore in remote repository %q",
+			latest.Commit, latest.Ref, remoteURL)
+	}
+	if err := checkTree(o, commit); err != nil {
+		return nil, version.ReleaseTag{}, fmt.Errorf(
+			"latest release %q in remote repository %q %w",
+			latest.Ref, remoteURL, err)
+	}
+	if err := o.Verify(repo, latest.Hash); err != nil {
+		return nil, version.ReleaseTag{}, fmt.Errorf(
+			"cannot verify latest release %q in remote repository %q, reason: %w",
+			latest.Ref, remoteURL, err)
+	}
+	gfs, err := NewForCommit(repo, commit)
+	if err != nil {
+		return nil, version.ReleaseTag{}, err
+	}
+	gfs.revision = latest.Ref
+	return gfs, latest, nil
+}
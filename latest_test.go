@@ -0,0 +1,60 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/thediveo/gitrepofs/version"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("opening the latest release", func() {
+
+	It("opens the latest release", func(ctx context.Context) {
+		gfs, latest := Successful2R(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher))
+		Expect(latest.Semver).To(Equal("v1.1.1"))
+		Expect(latest.Ref).To(Equal("refs/tags/v1.1.1"))
+		Expect(latest.Commit).To(Equal(commit.Hash))
+		Expect(gfs.commit.Hash).To(Equal(commit.Hash))
+		Expect(fs.ReadFile(gfs, "folder/subfolder/canary.txt")).To(ContainSubstring("chirp!"))
+	})
+
+	It("opens annotated tags and release branches", func(ctx context.Context) {
+		gfs, latest := Successful2R(OpenLatest(ctx, tmprepdir,
+			version.NewConstrainedMatcher(version.SemverTagMatcher, version.MustParseConstraint("1.0"))))
+		Expect(latest.Ref).To(Equal("refs/tags/v1.0.1"))
+		Expect(latest.IsAnnotated()).To(BeTrue())
+		Expect(gfs.commit.Hash).To(Equal(latest.Commit))
+
+		gfs, latest = Successful2R(OpenLatest(ctx, tmprepdir, version.NewBranchMatcher("release-", "")))
+		Expect(latest.Ref).To(Equal("refs/heads/release-1.1"))
+		Expect(gfs.commit.Hash).To(Equal(commit.Hash))
+	})
+
+	It("reports missing releases and mismatching commits", func(ctx context.Context) {
+		Expect(OpenLatest(ctx, tmprepdir, version.NewPrefixedTagMatcher("libfoo-"))).
+			Error().To(HaveOccurred())
+		Expect(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher,
+			WithExpectedCommit(commit.Hash.String()))).Error().NotTo(HaveOccurred())
+		Expect(OpenLatest(ctx, tmprepdir, version.NewBranchMatcher("stable/", ".x"),
+			WithExpectedCommit(commit.Hash.String()))).Error().To(MatchError(ErrCommitMismatch))
+	})
+
+})
@@ -0,0 +1,181 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LockFilename is the conventional name of lock files.
+const LockFilename = "gitrepofs.lock"
+
+// lockVersion is the version of the lock file format.
+const lockVersion = 1
+
+// Requirement describes an upstream repository revision to depend on.
+type Requirement struct {
+	// Name identifies the requirement in the lock; defaults to the URL.
+	Name string `json:"name,omitempty"`
+	// URL of the upstream (remote) repository.
+	URL string `json:"url"`
+	// Revision to resolve, such as a tag or branch name.
+	Revision string `json:"revision"`
+	// Paths optionally limits the locked files to the listed directories and
+	// files; defaults to all files.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Lock pins upstream requirements to specific commits and files, so that later
+// code generation runs can be reproduced and verified.
+type Lock struct {
+	// Version of the lock file format.
+	Version int `json:"version"`
+	// Upstreams lists the locked upstream requirements.
+	Upstreams []LockedUpstream `json:"upstreams"`
+}
+
+// LockedUpstream is a [Requirement] resolved to a specific commit.
+type LockedUpstream struct {
+	Requirement
+	// Commit is the hash of the commit the revision resolved to.
+	Commit string `json:"commit"`
+	// Files lists the files with their blob hashes.
+	Files []ManifestEntry `json:"files"`
+}
+
+// ResolveLock resolves the specified upstream requirements into a lock,
+// fetching each upstream revision and recording the commit the revision
+// resolves to, as well as the blob hashes of the required files.
+func ResolveLock(ctx context.Context, reqs []Requirement, opts ...Option) (*Lock, error) {
+	lock := &Lock{
+		Version:   lockVersion,
+		Upstreams: make([]LockedUpstream, 0, len(reqs)),
+	}
+	for _, req := range reqs {
+		if req.Name == "" {
+			req.Name = req.URL
+		}
+		gfs, err := NewForRevision(ctx, req.URL, req.Revision, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot lock %q, reason: %w", req.Name, err)
+		}
+		files, err := gfs.lockedFiles(req.Paths)
+		if err != nil {
+			return nil, fmt.Errorf("cannot lock %q, reason: %w", req.Name, err)
+		}
+		lock.Upstreams = append(lock.Upstreams, LockedUpstream{
+			Requirement: req,
+			Commit:      gfs.commit.Hash.String(),
+			Files:       files,
+		})
+	}
+	return lock, nil
+}
+
+// lockedFiles returns the files with their blob hashes in the specified
+// directories and files, or all files if no paths are specified.
+func (gfs *FS) lockedFiles(paths []string) ([]ManifestEntry, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files := []ManifestEntry{}
+	add := func(name string, entry object.TreeEntry) error {
+		switch entry.Mode {
+		case filemode.Regular, filemode.Executable, filemode.Symlink:
+			files = append(files, ManifestEntry{
+				Path: name,
+				Mode: entry.Mode.String(),
+				Hash: entry.Hash.String(),
+			})
+		}
+		return nil
+	}
+	for _, p := range paths {
+		if p != "." {
+			entry, err := gfs.tree.FindEntry(p)
+			if err != nil {
+				return nil, fmt.Errorf("no such file or directory %q", p)
+			}
+			if entry.Mode != filemode.Dir {
+				_ = add(p, *entry)
+				continue
+			}
+		}
+		if err := gfs.walkTree(p, add); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Upstream returns the locked upstream with the specified name, or nil if
+// there is no such upstream.
+func (l *Lock) Upstream(name string) *LockedUpstream {
+	for idx := range l.Upstreams {
+		if l.Upstreams[idx].Name == name {
+			return &l.Upstreams[idx]
+		}
+	}
+	return nil
+}
+
+// Open returns the [FS] for the locked upstream, failing with an error
+// wrapping [ErrCommitMismatch] if the upstream revision now resolves to a
+// different commit than locked, or wrapping [ErrInvalidHash] if the locked
+// commit is malformed.
+func (u *LockedUpstream) Open(ctx context.Context, opts ...Option) (*FS, error) {
+	opts = append(opts, WithExpectedCommit(u.Commit))
+	return NewForRevision(ctx, u.URL, u.Revision, opts...)
+}
+
+// ReadLock reads a lock from the named JSON file.
+func ReadLock(name string) (*Lock, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read lock, reason: %w", err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(b, &lock); err != nil {
+		return nil, fmt.Errorf("invalid lock %q, reason: %w", name, err)
+	}
+	if lock.Version != lockVersion {
+		return nil, fmt.Errorf("unsupported lock %q version %d", name, lock.Version)
+	}
+	for _, u := range lock.Upstreams {
+		if _, err := parseHash(u.Commit); err != nil {
+			return nil, fmt.Errorf("invalid lock %q, upstream %q has invalid commit, reason: %w",
+				name, u.Name, err)
+		}
+	}
+	return &lock, nil
+}
+
+// WriteLock writes the lock into the named JSON file.
+func WriteLock(name string, lock *Lock) error {
+	b, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal lock, reason: %w", err)
+	}
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write lock, reason: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("lock files", func() {
+
+	It("resolves requirements into a lock", func(ctx context.Context) {
+		lock := Successful(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v1.1.1", Paths: []string{"README", "folder/subfolder"}},
+			{Name: "initial", URL: tmprepdir, Revision: "v1.0"},
+		}))
+		Expect(lock.Version).To(Equal(1))
+		Expect(lock.Upstreams).To(HaveLen(2))
+
+		u := lock.Upstream(tmprepdir)
+		Expect(u).NotTo(BeNil())
+		Expect(u.Commit).To(Equal(commit.Hash.String()))
+		Expect(u.Files).To(ConsistOf(
+			HaveField("Path", "README"),
+			HaveField("Path", "folder/subfolder/canary.txt"),
+			And(HaveField("Path", "folder/subfolder/schkript.sh"), HaveField("Mode", "0100755")),
+		))
+
+		u = lock.Upstream("initial")
+		Expect(u).NotTo(BeNil())
+		Expect(u.Files).To(ConsistOf(HaveField("Path", "README")))
+
+		Expect(lock.Upstream("foobar")).To(BeNil())
+	})
+
+	It("reports unresolvable requirements", func(ctx context.Context) {
+		Expect(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v666"},
+		})).Error().To(HaveOccurred())
+		Expect(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v1.0", Paths: []string{"folder"}},
+		})).Error().To(HaveOccurred())
+	})
+
+	It("writes and reads lock files", func(ctx context.Context) {
+		lock := Successful(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v1.1.1"},
+		}))
+		tmpDir := Successful(os.MkdirTemp("", "gitrepofs-lock-*"))
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		name := filepath.Join(tmpDir, LockFilename)
+		Expect(WriteLock(name, lock)).To(Succeed())
+		Expect(ReadLock(name)).To(Equal(lock))
+
+		Expect(os.WriteFile(name, []byte(`{"version":42}`), 0o644)).To(Succeed())
+		Expect(ReadLock(name)).Error().To(HaveOccurred())
+		Expect(ReadLock(filepath.Join(tmpDir, "nada"))).Error().To(HaveOccurred())
+	})
+
+	It("refuses to proceed when a revision now resolves differently", func(ctx context.Context) {
+		lock := Successful(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v1.1.1"},
+		}))
+		gfs := Successful(lock.Upstreams[0].Open(ctx))
+		Expect(fs.ReadFile(gfs, "folder/subfolder/canary.txt")).Error().NotTo(HaveOccurred())
+
+		lock.Upstreams[0].Revision = "v1.0"
+		Expect(lock.Upstreams[0].Open(ctx)).Error().To(MatchError(ErrCommitMismatch))
+	})
+
+	It("refuses malformed locked commits", func(ctx context.Context) {
+		lock := Successful(ResolveLock(ctx, []Requirement{
+			{URL: tmprepdir, Revision: "v1.1.1"},
+		}))
+		tmpDir := Successful(os.MkdirTemp("", "gitrepofs-lock-*"))
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		name := filepath.Join(tmpDir, LockFilename)
+		for _, commit := range []string{"", "v1.1.1", strings.Repeat("0", 40)} {
+			lock.Upstreams[0].Commit = commit
+			Expect(lock.Upstreams[0].Open(ctx)).Error().To(MatchError(ErrInvalidHash))
+			Expect(WriteLock(name, lock)).To(Succeed())
+			Expect(ReadLock(name)).Error().To(MatchError(ErrInvalidHash))
+		}
+	})
+
+})
@@ -0,0 +1,251 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// LogEntry describes a commit touching a path, see [Log].
+type LogEntry struct {
+	// Hash of the commit.
+	Hash plumbing.Hash
+	// Time of the commit, that is, the committer time.
+	Time time.Time
+	// Author of the commit.
+	Author object.Signature
+	// Message of the commit.
+	Message string
+	// Path of the file or directory in this commit, relative to the root of
+	// the repository. When following renames, Path is the name before
+	// renaming for the commits preceding a rename.
+	Path string
+}
+
+// LogOption configures [Log].
+type LogOption func(*logOptions)
+
+type logOptions struct {
+	maxCount int
+	since    time.Time
+	until    time.Time
+	follow   bool
+}
+
+// WithMaxCount limits [Log] to the specified number of most recent commits. A
+// count of zero means no limit.
+func WithMaxCount(n int) LogOption {
+	return func(o *logOptions) { o.maxCount = n }
+}
+
+// WithSince limits [Log] to the commits committed at or after the specified
+// time.
+func WithSince(t time.Time) LogOption {
+	return func(o *logOptions) { o.since = t }
+}
+
+// WithUntil limits [Log] to the commits committed at or before the specified
+// time.
+func WithUntil(t time.Time) LogOption {
+	return func(o *logOptions) { o.until = t }
+}
+
+// WithFollowRenames makes [Log] continue listing the history of a file beyond
+// renames, similar to “git log --follow”. Only file renames are followed, not
+// directory renames.
+func WithFollowRenames() LogOption {
+	return func(o *logOptions) { o.follow = true }
+}
+
+// Log returns the commits touching the named file or directory, starting
+// with the commit of the file system and going back in history, ordered by
+// commit time with the most recent commit first. A commit touches a path when
+// the path's contents or mode differ from all parents of the commit, or when
+// the commit deletes the path present in all its parents, so merge commits
+// only show up when they actually change the path.
+//
+// Log works on the repository backing the file system, so the history is
+// limited to what has been cloned. The name is relative to the root of the
+// file system, also for file systems returned by [FS.Sub].
+func Log(ctx context.Context, gfs *FS, name string, opts ...LogOption) ([]LogEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "log", Path: name, Err: fs.ErrInvalid}
+	}
+	if gfs.commit == nil {
+		return nil, errors.New("no commit history for file system")
+	}
+	o := &logOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	current := path.Join(gfs.prefix, name)
+	if _, ok, err := entryAt(gfs.commit, current); err != nil || !ok {
+		return nil, &fs.PathError{Op: "log", Path: name, Err: fs.ErrNotExist}
+	}
+	commits := object.NewCommitIterCTime(gfs.commit, nil, nil)
+	defer commits.Close()
+	entries := []LogEntry{}
+	err := commits.ForEach(func(commit *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if o.maxCount > 0 && len(entries) >= o.maxCount {
+			return storer.ErrStop
+		}
+		touched, renamedFrom, err := touches(ctx, commit, current, o.follow)
+		if err != nil || !touched {
+			return err
+		}
+		entry := LogEntry{
+			Hash:    commit.Hash,
+			Time:    commit.Committer.When,
+			Author:  commit.Author,
+			Message: commit.Message,
+			Path:    current,
+		}
+		if renamedFrom != "" {
+			current = renamedFrom
+		}
+		if (!o.since.IsZero() && entry.Time.Before(o.since)) ||
+			(!o.until.IsZero() && entry.Time.After(o.until)) {
+			return nil
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot log %q, reason: %w", name, err)
+	}
+	return entries, nil
+}
+
+// touches returns true if the commit changes the named file or directory
+// compared to all its parents, including deleting it. When following renames
+// and the commit renamed the file, touches additionally returns the file's
+// name before the rename.
+func touches(ctx context.Context, commit *object.Commit, name string, follow bool) (bool, string, error) {
+	entry, ok, err := entryAt(commit, name)
+	if err != nil {
+		return false, "", err
+	}
+	if !ok {
+		deleted, err := deletes(commit, name)
+		return deleted, "", err
+	}
+	var without *object.Commit // first parent without the path.
+	same := false
+	err = commit.Parents().ForEach(func(parent *object.Commit) error {
+		parentEntry, ok, err := entryAt(parent, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if without == nil {
+				without = parent
+			}
+			return nil
+		}
+		if parentEntry.Hash == entry.Hash && parentEntry.Mode == entry.Mode {
+			same = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil || same {
+		return false, "", err
+	}
+	if !follow || without == nil || entry.Mode == filemode.Dir {
+		return true, "", nil
+	}
+	renamedFrom, err := renameSource(ctx, without, commit, name)
+	return true, renamedFrom, err
+}
+
+// renameSource returns the name of the file in the parent commit that got
+// renamed to the specified name in the commit, or an empty string if there
+// was no such rename.
+func renameSource(ctx context.Context, parent, commit *object.Commit, name string) (string, error) {
+	parentTree, err := parent.Tree()
+	if err != nil {
+		return "", err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", err
+	}
+	for _, change := range changes {
+		if change.To.Name == name && change.From.Name != "" && change.From.Name != name {
+			return change.From.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// deletes returns true if the commit deletes the named file or directory,
+// that is, the path is missing in the commit but present in all its parents.
+// Similar to “git log”, a merge commit doesn't delete a path that is already
+// missing in one of its parents.
+func deletes(commit *object.Commit, name string) (bool, error) {
+	if commit.NumParents() == 0 {
+		return false, nil
+	}
+	deleted := true
+	err := commit.Parents().ForEach(func(parent *object.Commit) error {
+		_, ok, err := entryAt(parent, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			deleted = false
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return deleted && err == nil, err
+}
+
+// entryAt returns the tree entry of the named file or directory in the
+// commit's tree, and true if the path exists in this commit.
+func entryAt(commit *object.Commit, name string) (object.TreeEntry, bool, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return object.TreeEntry{}, false, err
+	}
+	if name == "." {
+		return object.TreeEntry{Name: ".", Mode: filemode.Dir, Hash: tree.Hash}, true, nil
+	}
+	entry, err := tree.FindEntry(name)
+	if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+		return object.TreeEntry{}, false, nil
+	}
+	if err != nil {
+		return object.TreeEntry{}, false, err
+	}
+	return *entry, true, nil
+}
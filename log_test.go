@@ -0,0 +1,111 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+	"time"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("path history", Ordered, func() {
+
+	var gfs *FS
+
+	BeforeAll(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, localremote.CreateTransientHistoryRepo(), "HEAD"))
+	})
+
+	messages := func(entries []LogEntry) []string {
+		messages := []string{}
+		for _, entry := range entries {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+
+	It("lists the commits touching a file", func(ctx context.Context) {
+		entries := Successful(Log(ctx, gfs, "other.txt"))
+		Expect(messages(entries)).To(HaveExactElements("adds more to other", "adds notes"))
+		Expect(entries[0].Author.Name).To(Equal("Alice"))
+		Expect(entries[0].Time).To(BeTemporally("==", localremote.HistoryEpoch.Add(2*time.Hour)))
+		Expect(entries[0].Path).To(Equal("other.txt"))
+		Expect(entries[1].Hash).NotTo(Equal(entries[0].Hash))
+	})
+
+	It("lists the commits touching a directory", func(ctx context.Context) {
+		Expect(messages(Successful(Log(ctx, gfs, "docs")))).To(
+			HaveExactElements("adds delta", "moves notes into docs"))
+		Expect(Log(ctx, gfs, ".")).To(HaveLen(5))
+	})
+
+	It("follows renames", func(ctx context.Context) {
+		Expect(messages(Successful(Log(ctx, gfs, "docs/notes.md")))).To(
+			HaveExactElements("adds delta", "moves notes into docs"))
+
+		entries := Successful(Log(ctx, gfs, "docs/notes.md", WithFollowRenames()))
+		Expect(messages(entries)).To(HaveExactElements(
+			"adds delta", "moves notes into docs", "emphasizes beta", "adds notes"))
+		Expect(entries).To(HaveEach(HaveField("Path", BeElementOf("docs/notes.md", "notes.txt"))))
+		Expect(entries[1].Path).To(Equal("docs/notes.md"))
+		Expect(entries[2].Path).To(Equal("notes.txt"))
+	})
+
+	It("reports commits deleting a path", func(ctx context.Context) {
+		moves := Successful(gfs.repo.CommitObject(Successful(Log(ctx, gfs, "docs"))[1].Hash))
+		Expect(touches(ctx, moves, "notes.txt", false)).To(BeTrue())
+		Expect(touches(ctx, moves, "nada.txt", false)).To(BeFalse())
+
+		parent := Successful(moves.Parent(0))
+		Expect(touches(ctx, parent, "docs/notes.md", false)).To(BeFalse())
+	})
+
+	It("bounds the history", func(ctx context.Context) {
+		Expect(messages(Successful(Log(ctx, gfs, ".", WithMaxCount(2))))).To(
+			HaveExactElements("adds delta", "moves notes into docs"))
+		Expect(messages(Successful(Log(ctx, gfs, ".",
+			WithSince(localremote.HistoryEpoch.Add(time.Hour)),
+			WithUntil(localremote.HistoryEpoch.Add(2*time.Hour)))))).To(
+			HaveExactElements("adds more to other", "emphasizes beta"))
+		Expect(messages(Successful(Log(ctx, gfs, "docs/notes.md",
+			WithFollowRenames(), WithUntil(localremote.HistoryEpoch.Add(time.Hour)))))).To(
+			HaveExactElements("emphasizes beta", "adds notes"))
+	})
+
+	It("logs paths inside subtrees", func(ctx context.Context) {
+		subfs := Successful(gfs.sub("docs"))
+		entries := Successful(Log(ctx, subfs, "notes.md"))
+		Expect(messages(entries)).To(HaveExactElements("adds delta", "moves notes into docs"))
+		Expect(entries[0].Path).To(Equal("docs/notes.md"))
+	})
+
+	It("reports errors", func(ctx context.Context) {
+		Expect(Log(ctx, gfs, "/notes.txt")).Error().To(MatchError(fs.ErrInvalid))
+		Expect(Log(ctx, gfs, "notes.txt")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(Log(ctx, New(gfs.repo, gfs.tree, gfs.mtime), "docs")).Error().To(
+			MatchError("no commit history for file system"))
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(Log(ctx, gfs, "docs")).Error().To(MatchError(context.Canceled))
+	})
+
+})
@@ -0,0 +1,158 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// Manifest records the files extracted from an [FS] by [Extract], so that the
+// extracted files can later be verified using [VerifyManifest].
+type Manifest struct {
+	// Commit is the hash of the commit the files were extracted from, if
+	// known.
+	Commit string `json:"commit,omitempty"`
+	// Files lists the extracted files and symbolic links (but not the
+	// directories) in git's tree order.
+	Files []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes a single extracted file or symbolic link.
+type ManifestEntry struct {
+	// Path of the file, slash-separated and relative to the extraction
+	// directory.
+	Path string `json:"path"`
+	// Mode is the git file mode, such as “0100644”.
+	Mode string `json:"mode"`
+	// Hash is the git blob hash of the extracted file contents, or the link
+	// target of a symbolic link. Unless extracted using text conversion, this
+	// is the same as the hash of the blob in the git repository.
+	Hash string `json:"hash"`
+}
+
+// ReadManifest reads a manifest from the named JSON file.
+func ReadManifest(name string) (*Manifest, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest, reason: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %q, reason: %w", name, err)
+	}
+	return &m, nil
+}
+
+// WriteManifest writes the manifest into the named JSON file.
+func WriteManifest(name string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal manifest, reason: %w", err)
+	}
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write manifest, reason: %w", err)
+	}
+	return nil
+}
+
+// ErrManifestMismatch indicates that a directory doesn't match its manifest.
+var ErrManifestMismatch = errors.New("manifest mismatch")
+
+// VerifyManifest checks the files in the specified directory against the
+// manifest: all files and symbolic links listed in the manifest must exist
+// with the correct type, executable bit, and contents, and there must be no
+// other files. The files named in ignore are skipped, such as the manifest
+// file itself. The error returned wraps [ErrManifestMismatch] when the
+// directory contents don't match the manifest.
+func VerifyManifest(dir string, m *Manifest, ignore ...string) error {
+	expected := map[string]ManifestEntry{}
+	for _, e := range m.Files {
+		expected[e.Path] = e
+	}
+	var errs []error
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if slices.Contains(ignore, rel) {
+			return nil
+		}
+		e, ok := expected[rel]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: unexpected file %q", ErrManifestMismatch, rel))
+			return nil
+		}
+		delete(expected, rel)
+		mode, hash, err := hashFile(name, d)
+		if err != nil {
+			return err
+		}
+		if mode.String() != e.Mode || hash.String() != e.Hash {
+			errs = append(errs, fmt.Errorf("%w: changed file %q", ErrManifestMismatch, rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot verify manifest, reason: %w", err)
+	}
+	for _, e := range m.Files {
+		if _, ok := expected[e.Path]; ok {
+			errs = append(errs, fmt.Errorf("%w: missing file %q", ErrManifestMismatch, e.Path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hashFile returns the git file mode and the git blob hash of the named file
+// or symbolic link.
+func hashFile(name string, d fs.DirEntry) (filemode.FileMode, plumbing.Hash, error) {
+	if d.Type()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(name)
+		if err != nil {
+			return 0, plumbing.ZeroHash, err
+		}
+		return filemode.Symlink, plumbing.ComputeHash(plumbing.BlobObject, []byte(target)), nil
+	}
+	info, err := d.Info()
+	if err != nil {
+		return 0, plumbing.ZeroHash, err
+	}
+	mode := filemode.Regular
+	if info.Mode().Perm()&0o111 != 0 {
+		mode = filemode.Executable
+	}
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return 0, plumbing.ZeroHash, err
+	}
+	return mode, plumbing.ComputeHash(plumbing.BlobObject, contents), nil
+}
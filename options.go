@@ -0,0 +1,130 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/thediveo/gitrepofs/remote"
+)
+
+// Option configures how [NewForRevision] fetches and resolves a revision. Use
+// the options from the [remote] package to configure authentication, proxies,
+// CA bundles, and timeouts.
+type Option = remote.Option
+
+// ErrCommitMismatch indicates that a revision resolved to a different commit
+// than expected.
+var ErrCommitMismatch = errors.New("revision resolves to unexpected commit")
+
+// ErrTreeMismatch indicates that a revision resolved to a commit with a
+// different tree than expected.
+var ErrTreeMismatch = errors.New("revision resolves to unexpected tree")
+
+// ErrInvalidHash indicates a malformed commit or tree hash.
+var ErrInvalidHash = errors.New("invalid hash")
+
+// WithExpectedCommit makes [NewForRevision] fail with an error wrapping
+// [ErrCommitMismatch] if the revision doesn't resolve to the specified commit
+// hash, such as when a tag has been moved in the remote repository since
+// locking it. The hash must consist of 40 hex digits, otherwise
+// [NewForRevision] fails with an error wrapping [ErrInvalidHash].
+func WithExpectedCommit(hash string) Option {
+	return func(o *remote.Options) {
+		h, err := parseHash(hash)
+		if err != nil {
+			o.Fail(fmt.Errorf("invalid expected commit, reason: %w", err))
+			return
+		}
+		o.ExpectedCommit = h
+	}
+}
+
+// WithExpectedTree makes [NewForRevision] fail with an error wrapping
+// [ErrTreeMismatch] if the revision doesn't resolve to a commit with the
+// specified (root) tree hash. In contrast to [WithExpectedCommit], the tree
+// hash only depends on the contents, so it stays the same when the same
+// contents get committed again, such as after rebasing. The hash must
+// consist of 40 hex digits, otherwise [NewForRevision] fails with an error
+// wrapping [ErrInvalidHash].
+func WithExpectedTree(hash string) Option {
+	return func(o *remote.Options) {
+		h, err := parseHash(hash)
+		if err != nil {
+			o.Fail(fmt.Errorf("invalid expected tree, reason: %w", err))
+			return
+		}
+		o.ExpectedTree = h
+	}
+}
+
+// parseHash returns the hash in its hex representation, or an error wrapping
+// [ErrInvalidHash] if it doesn't consist of 40 hex digits or is all zeros.
+func parseHash(hash string) (plumbing.Hash, error) {
+	if !plumbing.IsHash(hash) {
+		return plumbing.ZeroHash, fmt.Errorf("%q is not a 40 hex digit hash: %w", hash, ErrInvalidHash)
+	}
+	h := plumbing.NewHash(hash)
+	if h.IsZero() {
+		return plumbing.ZeroHash, fmt.Errorf("%q is the zero hash: %w", hash, ErrInvalidHash)
+	}
+	return h, nil
+}
+
+// newOptions returns the options resulting from applying the specified
+// options in sequence, or the error of the first invalid option.
+func newOptions(opts []Option) (*remote.Options, error) {
+	o := remote.NewOptions(opts...)
+	if o.Err != nil {
+		return nil, o.Err
+	}
+	return o, nil
+}
+
+// listingOptions returns the specified options without any expected commit,
+// tree, and verifier, for passing them on to the version package listing the
+// remote references. The callers check the expected commit and tree, as well
+// as the signatures, themselves on the repositories they clone anyway.
+func listingOptions(opts []Option) []Option {
+	return append(slices.Clip(opts), func(o *remote.Options) {
+		o.ExpectedCommit = plumbing.ZeroHash
+		o.ExpectedTree = plumbing.ZeroHash
+		o.Verifier = nil
+	})
+}
+
+// checkCommit returns an error wrapping [ErrCommitMismatch] if an expected
+// commit has been set and the specified commit hash differs from it.
+func checkCommit(o *remote.Options, hash plumbing.Hash) error {
+	if o.ExpectedCommit.IsZero() || hash == o.ExpectedCommit {
+		return nil
+	}
+	return fmt.Errorf("resolves to commit %s instead of %s: %w",
+		hash, o.ExpectedCommit, ErrCommitMismatch)
+}
+
+// checkTree returns an error wrapping [ErrTreeMismatch] if an expected tree
+// has been set and the tree of the specified commit differs from it.
+func checkTree(o *remote.Options, commit *object.Commit) error {
+	if o.ExpectedTree.IsZero() || commit.TreeHash == o.ExpectedTree {
+		return nil
+	}
+	return fmt.Errorf("resolves to tree %s instead of %s: %w",
+		commit.TreeHash, o.ExpectedTree, ErrTreeMismatch)
+}
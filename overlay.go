@@ -0,0 +1,375 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Whiteout file names in the upper file system of an [Overlay], following
+// the OCI image layer conventions.
+const (
+	// WhiteoutPrefix marks a file named “.wh.<name>” as hiding the file or
+	// directory <name> of the lower file system in the same directory.
+	WhiteoutPrefix = ".wh."
+	// OpaqueWhiteout is a file that hides all lower file system entries of
+	// the directory it is placed in.
+	OpaqueWhiteout = WhiteoutPrefix + WhiteoutPrefix + ".opq"
+)
+
+var _ fs.FS = (*Overlay)(nil)
+
+// Overlay layers an optional upper file system, such as a local directory
+// opened using [os.DirFS], as well as unified diff patches on top of a
+// (lower) git file system:
+//   - files and directories in the upper file system take precedence over
+//     the same files and directories in the lower file system, while the
+//     listings of directories existing in both layers get merged. An upper
+//     file hides a lower directory of the same name with all its contents.
+//   - whiteout files in the upper file system hide files and directories in
+//     the lower file system, see [WhiteoutPrefix] and [OpaqueWhiteout]. The
+//     whiteout files themselves never show up.
+//   - patches get applied at read time to the merged contents of the upper
+//     and lower file systems; patches can also create, delete, and rename
+//     files, see [Overlay.AddPatches].
+//
+// An Overlay never writes to its layers. When the upper file system is
+// writable, such as a local directory, changes to it become visible
+// immediately.
+type Overlay struct {
+	lower *FS
+	upper fs.FS // optional
+
+	patches map[string][]*filePatch // patches by new file path
+	deleted map[string]bool         // file paths deleted or renamed by patches
+}
+
+// NewOverlay returns a new [Overlay] of the upper file system on top of the
+// lower git file system. The upper file system may be nil, when overlaying
+// only patches.
+func NewOverlay(lower *FS, upper fs.FS) *Overlay {
+	return &Overlay{
+		lower:   lower,
+		upper:   upper,
+		patches: map[string][]*filePatch{},
+		deleted: map[string]bool{},
+	}
+}
+
+// AddPatches adds the file patches contained in a unified diff, such as
+// produced by “diff -u” or “git diff”, to the overlay. The file paths in the
+// patches are relative to the root of the overlay, with any git “a/” and “b/”
+// prefixes removed. Patches of the same file are applied in the order they
+// were added; hunks that don't apply at their line numbers are searched for,
+// but there is no fuzzy matching of context lines.
+//
+// Git renames and mode changes are applied even without any hunks, while git
+// diffs that cannot be applied, such as binary patches and copies, make
+// AddPatches fail.
+//
+// AddPatches must not be called concurrently with accessing the overlay.
+func (o *Overlay) AddPatches(diff []byte) error {
+	fps, err := parsePatches(diff)
+	if err != nil {
+		return fmt.Errorf("invalid patch, reason: %w", err)
+	}
+	for _, fp := range fps {
+		for _, name := range []string{fp.oldPath, fp.newPath} {
+			if name != "" && (!fs.ValidPath(name) || name == ".") {
+				return fmt.Errorf("invalid patch file path %q", name)
+			}
+		}
+	}
+	for _, fp := range fps {
+		switch {
+		case fp.newPath == "":
+			delete(o.patches, fp.oldPath)
+			o.deleted[fp.oldPath] = true
+		case fp.oldPath == "":
+			o.patches[fp.newPath] = []*filePatch{fp}
+			delete(o.deleted, fp.newPath)
+		default:
+			chain := append(o.patches[fp.oldPath], fp)
+			if fp.oldPath != fp.newPath {
+				delete(o.patches, fp.oldPath)
+				o.deleted[fp.oldPath] = true
+				delete(o.deleted, fp.newPath)
+			}
+			o.patches[fp.newPath] = chain
+		}
+	}
+	return nil
+}
+
+// Open opens the named file or directory, following the rules of
+// [fs.FS.Open]. When Open returns an error, it is of type [*fs.PathError] with
+// the Op field set to "open", the Path field set to name, and the Err field
+// describing the problem.
+func (o *Overlay) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if chain, ok := o.patches[name]; ok {
+		info, contents, err := o.readPatched(name, chain)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &overlayFile{fileinfo: info, Reader: bytes.NewReader(contents)}, nil
+	}
+	if o.deleted[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	f, err := o.openLayers(name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) || !o.patchedBelow(name) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+		}
+		// the directory exists only implicitly because of patches creating
+		// new files inside it.
+		return o.openDir(name, NewFileInfo(
+			object.TreeEntry{Name: path.Base(name), Mode: filemode.Dir}, 0, o.lower.mtime))
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+	}
+	if !info.IsDir() {
+		return f, nil
+	}
+	_ = f.Close()
+	return o.openDir(name, info)
+}
+
+// openLayers opens the named file or directory from the upper file system,
+// or otherwise from the lower file system unless hidden by whiteouts. It
+// doesn't take patches into account.
+func (o *Overlay) openLayers(name string) (fs.File, error) {
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, WhiteoutPrefix) {
+			return nil, fs.ErrNotExist
+		}
+	}
+	if o.upper != nil {
+		if f, err := o.upper.Open(name); err == nil {
+			return f, nil
+		}
+	}
+	if o.lowerHidden(name) {
+		return nil, fs.ErrNotExist
+	}
+	return o.lower.Open(name)
+}
+
+// openDir returns the named directory with the merged entries of both layers
+// and the patches.
+func (o *Overlay) openDir(name string, info fs.FileInfo) (fs.File, error) {
+	entries := map[string]fs.DirEntry{}
+	if !o.lowerHidden(name) && !o.inUpper(path.Join(name, OpaqueWhiteout)) {
+		lowerEntries, _ := fs.ReadDir(o.lower, name)
+		for _, entry := range lowerEntries {
+			entries[entry.Name()] = entry
+		}
+	}
+	if o.upper != nil {
+		upperEntries, _ := fs.ReadDir(o.upper, name)
+		for _, entry := range upperEntries {
+			if hidden, ok := strings.CutPrefix(entry.Name(), WhiteoutPrefix); ok {
+				delete(entries, hidden)
+			}
+		}
+		for _, entry := range upperEntries {
+			if !strings.HasPrefix(entry.Name(), WhiteoutPrefix) {
+				entries[entry.Name()] = entry
+			}
+		}
+	}
+	for deleted := range o.deleted {
+		if path.Dir(deleted) == name {
+			delete(entries, path.Base(deleted))
+		}
+	}
+	for patched, chain := range o.patches {
+		rel, ok := relPath(name, patched)
+		if !ok {
+			continue
+		}
+		element, _, isDir := strings.Cut(rel, "/")
+		if isDir {
+			if _, ok := entries[element]; !ok {
+				entries[element] = NewDirEntry(
+					object.TreeEntry{Name: element, Mode: filemode.Dir}, 0, o.lower.mtime)
+			}
+			continue
+		}
+		patchedInfo, _, err := o.readPatched(patched, chain)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		entries[element] = fs.FileInfoToDirEntry(patchedInfo)
+	}
+	direntries := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		direntries = append(direntries, entry)
+	}
+	slices.SortFunc(direntries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return &virtualDir{fileinfo: info, entries: direntries}, nil
+}
+
+// readPatched returns the file information and contents of the named file
+// with its patches applied.
+func (o *Overlay) readPatched(name string, chain []*filePatch) (fs.FileInfo, []byte, error) {
+	var info fs.FileInfo = NewFileInfo(
+		object.TreeEntry{Name: path.Base(name), Mode: filemode.Regular}, 0, o.lower.mtime)
+	var contents []byte
+	if base := chain[0].oldPath; base != "" {
+		f, err := o.openLayers(base)
+		if err != nil {
+			return nil, nil, unwrapPathError(err)
+		}
+		defer func() { _ = f.Close() }()
+		if info, err = f.Stat(); err != nil {
+			return nil, nil, err
+		}
+		if info.IsDir() {
+			return nil, nil, fmt.Errorf("cannot patch directory %q", base)
+		}
+		if contents, err = io.ReadAll(f); err != nil {
+			return nil, nil, err
+		}
+	}
+	mode := info.Mode()
+	for _, fp := range chain {
+		var err error
+		if contents, err = fp.apply(contents); err != nil {
+			return nil, nil, fmt.Errorf("cannot patch %q, reason: %w", name, err)
+		}
+		if fp.mode != 0 {
+			mode = fp.mode
+		}
+	}
+	return &overlayFileInfo{
+		FileInfo: info,
+		name:     path.Base(name),
+		size:     int64(len(contents)),
+		mode:     mode,
+	}, contents, nil
+}
+
+// lowerHidden returns true if the named file or directory of the lower file
+// system is hidden by a whiteout or an opaque whiteout in the upper file
+// system, or because one of its parent directories is a non-directory in the
+// upper file system.
+func (o *Overlay) lowerHidden(name string) bool {
+	if o.upper == nil {
+		return false
+	}
+	for p := name; p != "."; p = path.Dir(p) {
+		dir := path.Dir(p)
+		if o.inUpper(path.Join(dir, WhiteoutPrefix+path.Base(p))) ||
+			o.inUpper(path.Join(dir, OpaqueWhiteout)) {
+			return true
+		}
+		if dir != "." {
+			if info, err := fs.Stat(o.upper, dir); err == nil && !info.IsDir() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inUpper returns true if the named file exists in the upper file system.
+func (o *Overlay) inUpper(name string) bool {
+	if o.upper == nil {
+		return false
+	}
+	_, err := fs.Stat(o.upper, name)
+	return err == nil
+}
+
+// patchedBelow returns true if there are patched files below the named
+// directory.
+func (o *Overlay) patchedBelow(dir string) bool {
+	for patched := range o.patches {
+		if _, ok := relPath(dir, patched); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// relPath returns the name relative to the specified directory, and true if
+// the name is located below the directory.
+func relPath(dir string, name string) (string, bool) {
+	if dir == "." {
+		return name, true
+	}
+	return strings.CutPrefix(name, dir+"/")
+}
+
+// unwrapPathError returns the error wrapped by a [*fs.PathError], or the error
+// itself otherwise.
+func unwrapPathError(err error) error {
+	var perr *fs.PathError
+	if errors.As(err, &perr) {
+		return perr.Err
+	}
+	return err
+}
+
+// overlayFileInfo describes a patched file, overriding the name, size, and
+// mode of the unpatched file.
+type overlayFileInfo struct {
+	fs.FileInfo
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+// Name returns the base name of the patched file.
+func (i *overlayFileInfo) Name() string { return i.name }
+
+// Size returns the size of the patched file contents.
+func (i *overlayFileInfo) Size() int64 { return i.size }
+
+// Mode returns the mode of the patched file.
+func (i *overlayFileInfo) Mode() fs.FileMode { return i.mode }
+
+// overlayFile is a patched file, with its contents held in memory.
+type overlayFile struct {
+	*bytes.Reader
+	fileinfo fs.FileInfo
+}
+
+var _ fs.File = (*overlayFile)(nil)
+
+// Stat returns information about this patched file.
+func (f *overlayFile) Stat() (fs.FileInfo, error) { return f.fileinfo, nil }
+
+// Close this patched file.
+func (f *overlayFile) Close() error { return nil }
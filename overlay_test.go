@@ -0,0 +1,172 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"io/fs"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("overlay file systems", func() {
+
+	var gfs *FS
+
+	BeforeEach(func() {
+		gfs = New(repo, Successful(commit.Tree()), commit.Author.When)
+	})
+
+	names := func(fsys fs.FS, dir string) []string {
+		names := []string{}
+		for _, direntry := range Successful(fs.ReadDir(fsys, dir)) {
+			names = append(names, direntry.Name())
+		}
+		return names
+	}
+
+	It("passes the lower file system through", func() {
+		o := NewOverlay(gfs, nil)
+		Expect(names(o, ".")).To(HaveExactElements("README", "fodder", "folder"))
+		Expect(fs.ReadFile(o, "folder/subfolder/canary.txt")).To(ContainSubstring("chirp!"))
+	})
+
+	It("merges upper and lower file systems", func() {
+		o := NewOverlay(gfs, fstest.MapFS{
+			"README":              {Data: []byte("upper README\n")},
+			"folder/local.txt":    {Data: []byte("local\n")},
+			"local/dir/file.txt":  {Data: []byte("local\n")},
+			"fodder/.wh.empty":    {},
+			"folder/.wh.nonexist": {},
+		})
+		Expect(names(o, ".")).To(HaveExactElements("README", "fodder", "folder", "local"))
+		Expect(names(o, "folder")).To(HaveExactElements("local.txt", "subfolder"))
+		Expect(names(o, "fodder")).To(BeEmpty())
+		Expect(fs.ReadFile(o, "README")).To(Equal([]byte("upper README\n")))
+		Expect(fs.ReadFile(o, "local/dir/file.txt")).To(Equal([]byte("local\n")))
+		Expect(fs.Stat(o, "fodder/empty")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(fs.Stat(o, "folder/.wh.nonexist")).Error().To(MatchError(fs.ErrNotExist))
+	})
+
+	It("hides lower directories", func() {
+		o := NewOverlay(gfs, fstest.MapFS{
+			".wh.fodder":                    {},
+			"folder/subfolder/.wh..wh..opq": {},
+			"folder/subfolder/new.txt":      {Data: []byte("new\n")},
+		})
+		Expect(names(o, ".")).To(HaveExactElements("README", "folder"))
+		Expect(fs.Stat(o, "fodder")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(fs.Stat(o, "fodder/empty")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(names(o, "folder/subfolder")).To(HaveExactElements("new.txt"))
+		Expect(fs.Stat(o, "folder/subfolder/canary.txt")).Error().To(MatchError(fs.ErrNotExist))
+	})
+
+	It("hides lower directories by upper files", func() {
+		o := NewOverlay(gfs, fstest.MapFS{
+			"folder": {Data: []byte("not a directory\n")},
+		})
+		Expect(fs.ReadFile(o, "folder")).To(Equal([]byte("not a directory\n")))
+		Expect(fs.Stat(o, "folder/subfolder")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(fs.Stat(o, "folder/subfolder/canary.txt")).Error().To(MatchError(fs.ErrNotExist))
+	})
+
+	It("applies patches at read time", func() {
+		o := NewOverlay(gfs, fstest.MapFS{
+			"folder/local.txt": {Data: []byte("local\n")},
+		})
+		Expect(o.AddPatches([]byte(`diff --git a/folder/local.txt b/folder/local.txt
+--- a/folder/local.txt
++++ b/folder/local.txt
+@@ -1 +1,2 @@
+ local
++patched
+--- /dev/null
++++ b/new/dir/created.txt
+@@ -0,0 +1 @@
++created
+--- a/fodder/empty
++++ /dev/null
+--- a/folder/subfolder/canary.txt
++++ b/folder/subfolder/moved.txt
+`))).To(Succeed())
+
+		Expect(fs.ReadFile(o, "folder/local.txt")).To(Equal([]byte("local\npatched\n")))
+		fi := Successful(fs.Stat(o, "folder/local.txt"))
+		Expect(fi.Name()).To(Equal("local.txt"))
+		Expect(fi.Size()).To(Equal(int64(len("local\npatched\n"))))
+		Expect(fs.ReadFile(o, "new/dir/created.txt")).To(Equal([]byte("created\n")))
+		Expect(Successful(fs.Stat(o, "new")).IsDir()).To(BeTrue())
+		Expect(fs.ReadFile(o, "folder/subfolder/moved.txt")).To(ContainSubstring("chirp!"))
+
+		Expect(names(o, ".")).To(HaveExactElements("README", "fodder", "folder", "new"))
+		Expect(names(o, "fodder")).To(BeEmpty())
+		Expect(names(o, "new/dir")).To(HaveExactElements("created.txt"))
+		Expect(names(o, "folder/subfolder")).To(HaveExactElements("moved.txt", "schkript.sh"))
+		Expect(fs.Stat(o, "folder/subfolder/canary.txt")).Error().To(MatchError(fs.ErrNotExist))
+	})
+
+	It("applies git renames and mode changes without hunks", func() {
+		o := NewOverlay(gfs, nil)
+		Expect(o.AddPatches([]byte(`diff --git a/folder/subfolder/canary.txt b/folder/moved.txt
+similarity index 100%
+rename from folder/subfolder/canary.txt
+rename to folder/moved.txt
+diff --git a/README b/README
+old mode 100644
+new mode 100755
+diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..e69de29
+`))).To(Succeed())
+		Expect(fs.Stat(o, "folder/subfolder/canary.txt")).Error().To(MatchError(fs.ErrNotExist))
+		Expect(fs.ReadFile(o, "folder/moved.txt")).To(ContainSubstring("chirp!"))
+		Expect(names(o, "folder")).To(HaveExactElements("moved.txt", "subfolder"))
+		Expect(Successful(fs.Stat(o, "README")).Mode()).To(Equal(fs.FileMode(0o755)))
+		Expect(fs.ReadFile(o, "new.txt")).To(BeEmpty())
+	})
+
+	It("reports patches not applying", func() {
+		o := NewOverlay(gfs, nil)
+		Expect(o.AddPatches([]byte("--- a/README\n+++ b/README\n@@ -1 +1 @@\n-nada\n+zilch\n"))).To(Succeed())
+		Expect(fs.ReadFile(o, "README")).Error().To(MatchError(ContainSubstring("hunk #1 does not apply")))
+	})
+
+	DescribeTable("rejects invalid patches",
+		func(diff string) {
+			Expect(NewOverlay(gfs, nil).AddPatches([]byte(diff))).NotTo(Succeed())
+		},
+		Entry(nil, "--- /dev/null\n+++ /dev/null\n"),
+		Entry(nil, "--- /dev/null\n+++ /etc/passwd\n"),
+		Entry(nil, "--- a/../README\n+++ b/README\n"),
+	)
+
+	DescribeTable("reports errors",
+		func(name string, experr error) {
+			_, err := NewOverlay(gfs, fstest.MapFS{}).Open(name)
+			var perr *fs.PathError
+			Expect(err).To(BeAssignableToTypeOf(perr))
+			perr = err.(*fs.PathError)
+			Expect(perr.Op).To(Equal("open"))
+			Expect(perr.Path).To(Equal(name))
+			Expect(perr.Err).To(Equal(experr))
+		},
+		Entry(nil, "/README", fs.ErrInvalid),
+		Entry(nil, "nada", fs.ErrNotExist),
+		Entry(nil, "folder/nada", fs.ErrNotExist),
+	)
+
+})
@@ -0,0 +1,331 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// devNull is the path used in unified diffs for missing old or new files.
+const devNull = "/dev/null"
+
+// filePatch is the unified diff of a single file.
+type filePatch struct {
+	oldPath string      // empty when creating a new file.
+	newPath string      // empty when deleting the file.
+	mode    fs.FileMode // new mode from a git diff, zero if unchanged.
+	hunks   []hunk
+}
+
+// hunk is a single hunk of a unified diff.
+type hunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	lines              []string // prefixed with ' ', '-', or '+', including "\n".
+}
+
+// hunkHeaderRegex matches hunk headers, such as "@@ -1,3 +1,4 @@ func foo()".
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// gitDiffPrefix starts the extended header of a file patch in a git diff.
+const gitDiffPrefix = "diff --git "
+
+// parsePatches parses the file patches contained in a unified diff, such as
+// produced by “diff -u” or “git diff”. Lines outside the file patches, such as
+// commit messages, are ignored. Paths in git's “a/” and “b/” notation get
+// stripped of these prefixes.
+//
+// The extended headers of git diffs are taken into account, so that renames
+// as well as newly created and deleted empty files don't need any hunks. Git
+// diffs that cannot be applied, such as binary patches and copies, are
+// rejected.
+func parsePatches(diff []byte) ([]*filePatch, error) {
+	lines := strings.Split(string(diff), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	patches := []*filePatch{}
+	for idx := 0; idx < len(lines); {
+		var fp *filePatch
+		var err error
+		switch {
+		case strings.HasPrefix(lines[idx], gitDiffPrefix):
+			fp, idx, err = parseGitPatch(lines, idx)
+		case isFileHeader(lines, idx):
+			fp = &filePatch{}
+			fp.oldPath, fp.newPath = fileHeaderPaths(lines, idx)
+			if fp.oldPath == "" && fp.newPath == "" {
+				return nil, fmt.Errorf("invalid patch in line %d: missing old and new file", idx+1)
+			}
+			fp.hunks, idx, err = parseHunks(lines, idx+2)
+		default:
+			idx++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, fp)
+	}
+	return patches, nil
+}
+
+// parseGitPatch parses the git diff file patch starting with the “diff --git”
+// line at lines[idx], returning the file patch and the index of the line
+// following it.
+func parseGitPatch(lines []string, idx int) (*filePatch, int, error) {
+	start := idx
+	fp := &filePatch{}
+	fp.oldPath, fp.newPath = gitDiffPaths(lines[idx][len(gitDiffPrefix):])
+	created, deleted := false, false
+	finish := func(next int) (*filePatch, int, error) {
+		if created {
+			fp.oldPath = ""
+		}
+		if deleted {
+			fp.newPath = ""
+		}
+		if fp.oldPath == "" && fp.newPath == "" {
+			return nil, 0, fmt.Errorf("invalid git diff header in line %d: missing old and new file", start+1)
+		}
+		return fp, next, nil
+	}
+	var err error
+	for idx++; idx < len(lines); idx++ {
+		line := lines[idx]
+		switch {
+		case isFileHeader(lines, idx):
+			fp.oldPath, fp.newPath = fileHeaderPaths(lines, idx)
+			if fp.hunks, idx, err = parseHunks(lines, idx+2); err != nil {
+				return nil, 0, err
+			}
+			return finish(idx)
+		case strings.HasPrefix(line, "new file mode "):
+			created = true
+			fp.mode, err = gitMode(line[len("new file mode "):], idx)
+		case strings.HasPrefix(line, "deleted file mode "):
+			deleted = true
+		case strings.HasPrefix(line, "new mode "):
+			fp.mode, err = gitMode(line[len("new mode "):], idx)
+		case strings.HasPrefix(line, "rename from "):
+			fp.oldPath = line[len("rename from "):]
+		case strings.HasPrefix(line, "rename to "):
+			fp.newPath = line[len("rename to "):]
+		case strings.HasPrefix(line, "old mode "),
+			strings.HasPrefix(line, "index "),
+			strings.HasPrefix(line, "similarity index "),
+			strings.HasPrefix(line, "dissimilarity index "):
+			// nothing to do.
+		case strings.HasPrefix(line, "copy from "), strings.HasPrefix(line, "copy to "):
+			return nil, 0, fmt.Errorf("cannot apply copy in line %d", idx+1)
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			return nil, 0, fmt.Errorf("cannot apply binary patch in line %d", idx+1)
+		default:
+			// end of this git file patch without any hunks.
+			return finish(idx)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return finish(idx)
+}
+
+// gitDiffPaths returns the old and new paths from a “diff --git a/old b/new”
+// line. As paths may contain spaces, this is only unambiguous if both paths
+// are the same; otherwise gitDiffPaths returns empty paths and the paths need
+// to come from the following extended header lines.
+func gitDiffPaths(s string) (string, string) {
+	if len(s)%2 == 0 {
+		return "", ""
+	}
+	half := len(s) / 2
+	a, b := s[:half], s[half+1:]
+	if s[half] != ' ' || strings.TrimPrefix(a, "a/") != strings.TrimPrefix(b, "b/") {
+		return "", ""
+	}
+	return strings.TrimPrefix(a, "a/"), strings.TrimPrefix(b, "b/")
+}
+
+// gitMode returns the file mode of a git diff mode header in the specified
+// line, such as “100755”. Only regular and executable file modes can be
+// applied.
+func gitMode(s string, idx int) (fs.FileMode, error) {
+	mode, err := filemode.New(s)
+	if err != nil || (mode != filemode.Regular && mode != filemode.Executable) {
+		return 0, fmt.Errorf("cannot apply file mode %q in line %d", s, idx+1)
+	}
+	return mode.ToOSFileMode()
+}
+
+// isFileHeader returns true if lines[idx] and the following line are the
+// “---” and “+++” lines of a file patch.
+func isFileHeader(lines []string, idx int) bool {
+	return strings.HasPrefix(lines[idx], "--- ") && idx+1 < len(lines) &&
+		strings.HasPrefix(lines[idx+1], "+++ ")
+}
+
+// fileHeaderPaths returns the old and new paths from the “---” and “+++” lines
+// starting at lines[idx].
+func fileHeaderPaths(lines []string, idx int) (string, string) {
+	return patchPath(lines[idx][4:], "a/"), patchPath(lines[idx+1][4:], "b/")
+}
+
+// parseHunks parses the hunks starting at lines[idx], returning the hunks and
+// the index of the line following the last hunk.
+func parseHunks(lines []string, idx int) ([]hunk, int, error) {
+	var hunks []hunk
+	for idx < len(lines) && strings.HasPrefix(lines[idx], "@@ ") {
+		h, next, err := parseHunk(lines, idx)
+		if err != nil {
+			return nil, 0, err
+		}
+		hunks = append(hunks, h)
+		idx = next
+	}
+	return hunks, idx, nil
+}
+
+// patchPath returns the path from a “---” or “+++” line, stripped of any
+// trailing time stamp and the specified git prefix, or an empty string for
+// /dev/null.
+func patchPath(s string, prefix string) string {
+	s, _, _ = strings.Cut(s, "\t")
+	s = strings.TrimSpace(s)
+	if s == devNull {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// parseHunk parses the hunk starting with the hunk header at lines[idx],
+// returning the hunk and the index of the line following the hunk.
+func parseHunk(lines []string, idx int) (hunk, int, error) {
+	m := hunkHeaderRegex.FindStringSubmatch(lines[idx])
+	if m == nil {
+		return hunk{}, 0, fmt.Errorf("invalid hunk header in line %d", idx+1)
+	}
+	number := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	h := hunk{
+		oldStart: number(m[1]),
+		oldLines: number(m[2]),
+		newStart: number(m[3]),
+		newLines: number(m[4]),
+	}
+	oldRemaining, newRemaining := h.oldLines, h.newLines
+	idx++
+	for ; idx < len(lines); idx++ {
+		line := lines[idx]
+		if strings.HasPrefix(line, `\`) { // "\ No newline at end of file"
+			if len(h.lines) > 0 {
+				last := len(h.lines) - 1
+				h.lines[last] = strings.TrimSuffix(h.lines[last], "\n")
+			}
+			continue
+		}
+		if oldRemaining == 0 && newRemaining == 0 {
+			break
+		}
+		if line == "" { // some tools strip the trailing space of empty context lines.
+			line = " "
+		}
+		switch line[0] {
+		case ' ':
+			oldRemaining--
+			newRemaining--
+		case '-':
+			oldRemaining--
+		case '+':
+			newRemaining--
+		default:
+			return hunk{}, 0, fmt.Errorf("invalid hunk line %d", idx+1)
+		}
+		if oldRemaining < 0 || newRemaining < 0 {
+			return hunk{}, 0, fmt.Errorf("hunk too long in line %d", idx+1)
+		}
+		h.lines = append(h.lines, line+"\n")
+	}
+	if oldRemaining != 0 || newRemaining != 0 {
+		return hunk{}, 0, fmt.Errorf("truncated hunk in line %d", idx)
+	}
+	return h, idx, nil
+}
+
+// apply returns the specified contents with the file patch applied. Hunks
+// that don't apply at their specified line numbers are searched for, as long
+// as they apply after the previous hunk.
+func (fp *filePatch) apply(contents []byte) ([]byte, error) {
+	lines := strings.SplitAfter(string(contents), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	offset := 0 // line offset caused by the previously applied hunks.
+	floor := 0  // the next hunk must not apply before this line.
+	for idx, h := range fp.hunks {
+		var from, to []string
+		for _, line := range h.lines {
+			if line[0] != '+' {
+				from = append(from, line[1:])
+			}
+			if line[0] != '-' {
+				to = append(to, line[1:])
+			}
+		}
+		expected := h.oldStart - 1
+		if h.oldLines == 0 {
+			expected++ // pure insertions go after the specified line.
+		}
+		pos := locate(lines, from, expected+offset, floor)
+		if pos < 0 {
+			return nil, fmt.Errorf("hunk #%d does not apply", idx+1)
+		}
+		lines = slices.Replace(lines, pos, pos+len(from), to...)
+		offset = pos - expected + len(to) - len(from)
+		floor = pos + len(to)
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// locate returns the position of the from lines in lines, trying the
+// specified position first, and then searching outwards from it, but not
+// before floor. It returns -1 if the from lines cannot be found.
+func locate(lines []string, from []string, pos int, floor int) int {
+	matches := func(at int) bool {
+		return at >= floor && at+len(from) <= len(lines) &&
+			slices.Equal(lines[at:at+len(from)], from)
+	}
+	for delta := 0; pos-delta >= floor || pos+delta <= len(lines); delta++ {
+		if matches(pos - delta) {
+			return pos - delta
+		}
+		if matches(pos + delta) {
+			return pos + delta
+		}
+	}
+	return -1
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("unified diff patches", func() {
+
+	const contents = "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+
+	It("parses git diffs", func() {
+		fps := Successful(parsePatches([]byte(`diff --git a/foo b/foo
+index 0123456..789abcd 100644
+--- a/foo
++++ b/foo
+@@ -1,2 +1,2 @@
+-one
++uno
+ two
+@@ -9 +9,2 @@ context
+ nine
++nine and a half
+diff --git a/bar b/bar
+new file mode 100644
+--- /dev/null
++++ b/bar	2026-01-01 00:00:00
+@@ -0,0 +1 @@
++bar
+\ No newline at end of file
+`)))
+		Expect(fps).To(HaveLen(2))
+		Expect(fps[0].oldPath).To(Equal("foo"))
+		Expect(fps[0].newPath).To(Equal("foo"))
+		Expect(fps[0].hunks).To(HaveLen(2))
+		Expect(fps[0].hunks[1]).To(Equal(hunk{
+			oldStart: 9, oldLines: 1,
+			newStart: 9, newLines: 2,
+			lines: []string{" nine\n", "+nine and a half\n"},
+		}))
+		Expect(fps[1].oldPath).To(BeEmpty())
+		Expect(fps[1].newPath).To(Equal("bar"))
+		Expect(fps[1].hunks[0].lines).To(HaveExactElements("+bar"))
+	})
+
+	It("parses git extended headers without hunks", func() {
+		fps := Successful(parsePatches([]byte(`diff --git a/old name b/new name
+similarity index 100%
+rename from old name
+rename to new name
+diff --git a/empty b/empty
+new file mode 100644
+index 0000000..e69de29
+diff --git a/gone b/gone
+deleted file mode 100644
+index e69de29..0000000
+diff --git a/script.sh b/script.sh
+old mode 100644
+new mode 100755
+`)))
+		Expect(fps).To(HaveLen(4))
+		Expect(*fps[0]).To(Equal(filePatch{oldPath: "old name", newPath: "new name"}))
+		Expect(*fps[1]).To(Equal(filePatch{newPath: "empty", mode: 0o644}))
+		Expect(*fps[2]).To(Equal(filePatch{oldPath: "gone"}))
+		Expect(*fps[3]).To(Equal(filePatch{oldPath: "script.sh", newPath: "script.sh", mode: 0o755}))
+	})
+
+	DescribeTable("rejects invalid diffs",
+		func(diff string) {
+			Expect(parsePatches([]byte(diff))).Error().To(HaveOccurred())
+		},
+		Entry("both /dev/null", "--- /dev/null\n+++ /dev/null\n"),
+		Entry("invalid hunk header", "--- a/foo\n+++ b/foo\n@@ -x +1 @@\n"),
+		Entry("truncated hunk", "--- a/foo\n+++ b/foo\n@@ -1,2 +1,2 @@\n-one\n+uno\n"),
+		Entry("invalid hunk line", "--- a/foo\n+++ b/foo\n@@ -1,2 +1,2 @@\n-one\n+uno\n?two\n"),
+		Entry("binary git diff", "diff --git a/foo b/foo\nindex 0123456..789abcd 100644\nBinary files a/foo and b/foo differ\n"),
+		Entry("git binary patch", "diff --git a/foo b/foo\nindex 0123456..789abcd 100644\nGIT binary patch\nliteral 0\n"),
+		Entry("git copy", "diff --git a/foo b/bar\nsimilarity index 100%\ncopy from foo\ncopy to bar\n"),
+		Entry("git symlink", "diff --git a/foo b/foo\nnew file mode 120000\n"),
+		Entry("ambiguous git paths", "diff --git a/foo b/bar\nindex 0123456..789abcd 100644\n"),
+	)
+
+	It("applies hunks, even when shifted", func() {
+		fps := Successful(parsePatches([]byte(`--- foo
++++ foo
+@@ -3,3 +3,2 @@
+ three
+-four
+ five
+@@ -10,0 +10,1 @@
++eleven
+`)))
+		Expect(string(Successful(fps[0].apply([]byte(contents))))).To(Equal(
+			"one\ntwo\nthree\nfive\nsix\nseven\neight\nnine\nten\neleven\n"))
+		Expect(string(Successful(fps[0].apply([]byte("zero\n" + contents))))).To(Equal(
+			"zero\none\ntwo\nthree\nfive\nsix\nseven\neight\nnine\nten\neleven\n"))
+	})
+
+	It("creates and deletes contents", func() {
+		fps := Successful(parsePatches([]byte(`--- /dev/null
++++ b/foo
+@@ -0,0 +1,2 @@
++foo
++bar
+\ No newline at end of file
+--- a/foo
++++ /dev/null
+@@ -1,2 +0,0 @@
+-foo
+-bar
+\ No newline at end of file
+`)))
+		created := Successful(fps[0].apply(nil))
+		Expect(string(created)).To(Equal("foo\nbar"))
+		Expect(fps[1].apply(created)).To(BeEmpty())
+	})
+
+	It("rejects hunks not applying", func() {
+		fps := Successful(parsePatches([]byte("--- foo\n+++ foo\n@@ -1 +1 @@\n-zero\n+null\n")))
+		Expect(fps[0].apply([]byte(contents))).Error().To(MatchError("hunk #1 does not apply"))
+	})
+
+})
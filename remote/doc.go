@@ -0,0 +1,18 @@
+/*
+Package remote provides the options for accessing remote git repositories,
+such as authentication, proxies, custom CA bundles, and timeouts. The same
+[Option] type is accepted by both the [github.com/thediveo/gitrepofs] functions
+cloning remote repositories and the [github.com/thediveo/gitrepofs/version]
+functions listing remote references.
+
+	gfs, err := gitrepofs.NewForRevision(ctx, remoteURL, "v1.2.3",
+	    remote.WithAuth(&http.BasicAuth{Username: "foo", Password: token}),
+	    remote.WithTimeout(30*time.Second))
+
+Use [WithVerifier] to require the revision's annotated tag or commit to carry
+a valid signature, see [github.com/thediveo/gitrepofs/signature] for OpenPGP
+and SSH verifiers. Functions that don't fetch and verify a particular
+revision, such as listing release tags, reject a verifier instead of silently
+ignoring it.
+*/
+package remote
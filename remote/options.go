@@ -0,0 +1,221 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Option configures how remote git repositories are accessed.
+type Option func(*Options)
+
+// ErrUnsupportedOption indicates that an option has been passed to a function
+// not supporting it, such as an expected commit when only listing references.
+var ErrUnsupportedOption = errors.New("unsupported option")
+
+// Verifier verifies the signatures of commits and annotated tags, such as
+// the verifiers from the [github.com/thediveo/gitrepofs/signature] package.
+type Verifier interface {
+	// VerifyCommit returns nil if the commit carries a valid signature by a
+	// trusted key, otherwise an error.
+	VerifyCommit(commit *object.Commit) error
+	// VerifyTag returns nil if the annotated tag carries a valid signature by
+	// a trusted key, otherwise an error.
+	VerifyTag(tag *object.Tag) error
+}
+
+// Options for accessing remote git repositories. Use [NewOptions] to create
+// Options from a list of [Option] functions.
+type Options struct {
+	// Auth is the authentication method, if any.
+	Auth transport.AuthMethod
+	// Proxy optionally specifies a proxy to use.
+	Proxy transport.ProxyOptions
+	// CABundle optionally specifies additional PEM-encoded CA certificates.
+	CABundle []byte
+	// InsecureSkipTLS skips TLS server certificate verification.
+	InsecureSkipTLS bool
+	// Timeout limits the duration of remote operations, if non-zero.
+	Timeout time.Duration
+	// ExpectedCommit, if non-zero, is the commit a revision must resolve to;
+	// see [github.com/thediveo/gitrepofs.WithExpectedCommit]. Functions not
+	// resolving a particular revision, such as those of the
+	// [github.com/thediveo/gitrepofs/version] package, reject it; see
+	// [Options.RejectPins].
+	ExpectedCommit plumbing.Hash
+	// ExpectedTree, if non-zero, is the tree of the commit a revision must
+	// resolve to; see [github.com/thediveo/gitrepofs.WithExpectedTree]. It is
+	// rejected in the same way as ExpectedCommit.
+	ExpectedTree plumbing.Hash
+	// Verifier, if non-nil, verifies the signature of the resolved annotated
+	// tag or commit; see [WithVerifier]. Functions not verifying what they
+	// fetch reject it; see [Options.RejectVerifier].
+	Verifier Verifier
+	// Err is the first error encountered while applying the options, such as
+	// a malformed expected commit hash. Functions taking options fail with
+	// this error instead of silently ignoring invalid options.
+	Err error
+}
+
+// WithAuth authenticates with the specified method, such as
+// [github.com/go-git/go-git/v5/plumbing/transport/http.BasicAuth] or
+// [github.com/go-git/go-git/v5/plumbing/transport/ssh.PublicKeys].
+func WithAuth(auth transport.AuthMethod) Option {
+	return func(o *Options) {
+		o.Auth = auth
+	}
+}
+
+// WithProxy accesses remote repositories through the proxy with the specified
+// URL, such as "http://proxy.example.org:3128", and optional credentials.
+func WithProxy(url string, username string, password string) Option {
+	return func(o *Options) {
+		o.Proxy = transport.ProxyOptions{
+			URL:      url,
+			Username: username,
+			Password: password,
+		}
+	}
+}
+
+// WithCABundle additionally trusts the specified PEM-encoded CA certificates
+// when verifying TLS server certificates, such as for corporate CAs.
+func WithCABundle(pem []byte) Option {
+	return func(o *Options) {
+		o.CABundle = pem
+	}
+}
+
+// WithInsecureSkipTLS skips verifying TLS server certificates. Please use
+// this only for testing.
+func WithInsecureSkipTLS() Option {
+	return func(o *Options) {
+		o.InsecureSkipTLS = true
+	}
+}
+
+// WithTimeout limits the duration of each remote operation, such as cloning a
+// repository or listing its references.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithVerifier requires the resolved annotated tag, or otherwise the resolved
+// commit, to carry a valid signature accepted by the specified verifier, such
+// as a verifier from the [github.com/thediveo/gitrepofs/signature] package.
+func WithVerifier(v Verifier) Option {
+	return func(o *Options) {
+		o.Verifier = v
+	}
+}
+
+// NewOptions returns the Options resulting from applying the specified
+// options in sequence.
+func NewOptions(opts ...Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Fail records the specified error from applying an option, unless an error
+// has already been recorded before; see [Options.Err].
+func (o *Options) Fail(err error) {
+	if o.Err == nil {
+		o.Err = err
+	}
+}
+
+// RejectPins returns an error wrapping [ErrUnsupportedOption] if an expected
+// commit or tree has been set. Functions not resolving a particular revision
+// call RejectPins, so that these options never get silently ignored.
+func (o *Options) RejectPins() error {
+	if o.ExpectedCommit.IsZero() && o.ExpectedTree.IsZero() {
+		return nil
+	}
+	return fmt.Errorf("expected commit or tree: %w", ErrUnsupportedOption)
+}
+
+// RejectVerifier returns an error wrapping [ErrUnsupportedOption] if a
+// verifier has been set. Functions not verifying signatures call
+// RejectVerifier, so that callers asking for signed releases never silently
+// get unverified results.
+func (o *Options) RejectVerifier() error {
+	if o.Verifier == nil {
+		return nil
+	}
+	return fmt.Errorf("verifier: %w", ErrUnsupportedOption)
+}
+
+// Context returns a context derived from ctx that gets cancelled when the
+// configured timeout expires, if any. Callers must always call the returned
+// cancel function.
+func (o *Options) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, o.Timeout)
+}
+
+// CloneOptions returns the clone options for the specified remote URL.
+func (o *Options) CloneOptions(url string) *git.CloneOptions {
+	return &git.CloneOptions{
+		URL:             url,
+		Auth:            o.Auth,
+		ProxyOptions:    o.Proxy,
+		CABundle:        o.CABundle,
+		InsecureSkipTLS: o.InsecureSkipTLS,
+	}
+}
+
+// ListOptions returns the options for listing the references in a remote
+// repository, including the peeled references of annotated tags.
+func (o *Options) ListOptions() *git.ListOptions {
+	return &git.ListOptions{
+		Auth:            o.Auth,
+		ProxyOptions:    o.Proxy,
+		CABundle:        o.CABundle,
+		InsecureSkipTLS: o.InsecureSkipTLS,
+		PeelingOption:   git.AppendPeeled,
+	}
+}
+
+// Verify verifies the signature of the annotated tag or commit with the
+// specified hash in the repository using the configured verifier. Verify
+// returns nil if no verifier has been configured.
+func (o *Options) Verify(repo *git.Repository, hash plumbing.Hash) error {
+	if o.Verifier == nil {
+		return nil
+	}
+	if tag, err := repo.TagObject(hash); err == nil {
+		return o.Verifier.VerifyTag(tag)
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return err
+	}
+	return o.Verifier.VerifyCommit(commit)
+}
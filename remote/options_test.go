@@ -0,0 +1,119 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// nopVerifier accepts all commits and tags.
+type nopVerifier struct{}
+
+func (nopVerifier) VerifyCommit(*object.Commit) error { return nil }
+func (nopVerifier) VerifyTag(*object.Tag) error       { return nil }
+
+var _ = Describe("remote access options", func() {
+
+	It("defaults to plain access", func() {
+		o := NewOptions()
+		Expect(o).To(Equal(&Options{}))
+		Expect(o.CloneOptions("https://example.org/foo")).To(Equal(&git.CloneOptions{
+			URL: "https://example.org/foo",
+		}))
+		Expect(o.ListOptions()).To(Equal(&git.ListOptions{
+			PeelingOption: git.AppendPeeled,
+		}))
+	})
+
+	It("applies options", func() {
+		auth := &http.BasicAuth{Username: "foo", Password: "bar"}
+		pem := []byte("-----BEGIN CERTIFICATE-----")
+		o := NewOptions(
+			WithAuth(auth),
+			WithProxy("http://proxy.example.org:3128", "baz", "secret"),
+			WithCABundle(pem),
+			WithInsecureSkipTLS(),
+			WithTimeout(42*time.Second))
+		proxy := transport.ProxyOptions{
+			URL:      "http://proxy.example.org:3128",
+			Username: "baz",
+			Password: "secret",
+		}
+		Expect(o.Timeout).To(Equal(42 * time.Second))
+		Expect(o.CloneOptions("https://example.org/foo")).To(Equal(&git.CloneOptions{
+			URL:             "https://example.org/foo",
+			Auth:            auth,
+			ProxyOptions:    proxy,
+			CABundle:        pem,
+			InsecureSkipTLS: true,
+		}))
+		Expect(o.ListOptions()).To(Equal(&git.ListOptions{
+			Auth:            auth,
+			ProxyOptions:    proxy,
+			CABundle:        pem,
+			InsecureSkipTLS: true,
+			PeelingOption:   git.AppendPeeled,
+		}))
+	})
+
+	It("derives contexts with and without timeouts", func() {
+		ctx, cancel := NewOptions().Context(context.Background())
+		_, ok := ctx.Deadline()
+		Expect(ok).To(BeFalse())
+		cancel()
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+
+		ctx, cancel = NewOptions(WithTimeout(time.Hour)).Context(context.Background())
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+	})
+
+	It("records only the first option error", func() {
+		errFirst := errors.New("first")
+		o := NewOptions(
+			func(o *Options) { o.Fail(errFirst) },
+			func(o *Options) { o.Fail(errors.New("second")) })
+		Expect(o.Err).To(BeIdenticalTo(errFirst))
+	})
+
+	It("rejects pins", func() {
+		Expect(NewOptions().RejectPins()).To(Succeed())
+		Expect(NewOptions(func(o *Options) {
+			o.ExpectedCommit = plumbing.NewHash("0123456789012345678901234567890123456789")
+		}).RejectPins()).To(MatchError(ErrUnsupportedOption))
+		Expect(NewOptions(func(o *Options) {
+			o.ExpectedTree = plumbing.NewHash("0123456789012345678901234567890123456789")
+		}).RejectPins()).To(MatchError(ErrUnsupportedOption))
+	})
+
+	It("rejects verifiers", func() {
+		Expect(NewOptions().RejectVerifier()).To(Succeed())
+		Expect(NewOptions(WithVerifier(nopVerifier{})).RejectVerifier()).To(MatchError(ErrUnsupportedOption))
+	})
+
+})
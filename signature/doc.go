@@ -0,0 +1,18 @@
+/*
+Package signature verifies the OpenPGP and SSH signatures of git commits and
+annotated tags, such as in order to only accept releases signed by upstream
+maintainers.
+
+Create a [Verifier] from an armored OpenPGP key ring using
+[NewOpenPGPVerifier], or from an SSH “allowed signers” file (see
+ssh-keygen(1)) using [NewSSHVerifier], and pass it using
+[github.com/thediveo/gitrepofs/remote.WithVerifier]:
+
+	v, err := signature.NewSSHVerifier(allowedSigners)
+	gfs, err := gitrepofs.NewForRevision(ctx, remoteURL, "v1.2.3",
+	    remote.WithVerifier(v))
+
+Failed verifications return a [*VerificationError], wrapping either
+[ErrUnsigned], [ErrUnknownKey], or [ErrInvalidSignature].
+*/
+package signature
@@ -0,0 +1,242 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSH signature format constants, see
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig.
+const (
+	sshSignatureArmorStart = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureArmorEnd   = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic      = "SSHSIG"
+	sshSignatureVersion    = 1
+	sshSignatureNamespace  = "git"
+)
+
+// allowedSigner is a trusted SSH key from an allowed signers file, optionally
+// valid only after and/or before certain points in time.
+type allowedSigner struct {
+	principals  string
+	key         ssh.PublicKey
+	validAfter  time.Time
+	validBefore time.Time
+}
+
+// validAt returns true if the allowed signer is valid at the specified time.
+func (s allowedSigner) validAt(when time.Time) bool {
+	return (s.validAfter.IsZero() || !when.Before(s.validAfter)) &&
+		(s.validBefore.IsZero() || when.Before(s.validBefore))
+}
+
+// parseAllowedSigners parses an allowed signers file, as described in the
+// “ALLOWED SIGNERS” section of ssh-keygen(1). Certificate authorities as well
+// as keys restricted to other namespaces than “git” are skipped. The
+// “valid-after” and “valid-before” options are checked later against the
+// time of the signed object.
+func parseAllowedSigners(data []byte) ([]allowedSigner, error) {
+	signers := []allowedSigner{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		principals, rest, _ := strings.Cut(line, " ")
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed signer in line %d, reason: %w", lineno, err)
+		}
+		if !gitNamespaceAllowed(options) {
+			continue
+		}
+		signer := allowedSigner{principals: principals, key: key}
+		if err := signer.parseValidity(options); err != nil {
+			return nil, fmt.Errorf("invalid allowed signer in line %d, reason: %w", lineno, err)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, scanner.Err()
+}
+
+// gitNamespaceAllowed returns true if the allowed signer options permit
+// signatures in the “git” namespace and don't mark the key as a certificate
+// authority.
+func gitNamespaceAllowed(options []string) bool {
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		switch strings.ToLower(name) {
+		case "cert-authority":
+			return false
+		case "namespaces":
+			if !slices.Contains(strings.Split(strings.Trim(value, `"`), ","), sshSignatureNamespace) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseValidity sets the validity interval of the allowed signer from its
+// “valid-after” and “valid-before” options, if present.
+func (s *allowedSigner) parseValidity(options []string) error {
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		var validity *time.Time
+		switch strings.ToLower(name) {
+		case "valid-after":
+			validity = &s.validAfter
+		case "valid-before":
+			validity = &s.validBefore
+		default:
+			continue
+		}
+		t, err := parseSSHTime(strings.Trim(value, `"`))
+		if err != nil {
+			return fmt.Errorf("invalid %s option, reason: %w", strings.ToLower(name), err)
+		}
+		*validity = t
+	}
+	return nil
+}
+
+// parseSSHTime parses a timestamp in the “YYYYMMDD[Z]” or
+// “YYYYMMDDHHMM[SS][Z]” format of ssh-keygen(1). Timestamps are in the local
+// time zone, unless suffixed with “Z” for UTC.
+func parseSSHTime(value string) (time.Time, error) {
+	loc := time.Local
+	if v, ok := strings.CutSuffix(value, "Z"); ok {
+		value, loc = v, time.UTC
+	}
+	var layout string
+	switch len(value) {
+	case len("20060102"):
+		layout = "20060102"
+	case len("200601021504"):
+		layout = "200601021504"
+	case len("20060102150405"):
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("malformed timestamp %q", value)
+	}
+	return time.ParseInLocation(layout, value, loc)
+}
+
+// sshSignature is the wire format of an SSH signature, following the magic
+// preamble.
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      []byte
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// signedData is the wire format of the data actually signed, following the
+// magic preamble.
+type signedData struct {
+	Namespace     string
+	Reserved      []byte
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// verifySSHSignature verifies the armored SSH signature of the message,
+// accepting only signatures by the allowed signers valid at the specified
+// time of the signed object.
+func verifySSHSignature(signers []allowedSigner, message []byte, armored string, when time.Time) error {
+	blob, err := decodeSSHArmor(armored)
+	if err != nil {
+		return err
+	}
+	var sig sshSignature
+	if err := ssh.Unmarshal(blob, &sig); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if sig.Version != sshSignatureVersion || sig.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("%w: unsupported SSH signature version %d or namespace %q",
+			ErrInvalidSignature, sig.Version, sig.Namespace)
+	}
+	pubkey, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if !slices.ContainsFunc(signers, func(s allowedSigner) bool {
+		return bytes.Equal(s.key.Marshal(), pubkey.Marshal()) && s.validAt(when)
+	}) {
+		return ErrUnknownKey
+	}
+	var hash []byte
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h := sha256.Sum256(message)
+		hash = h[:]
+	case "sha512":
+		h := sha512.Sum512(message)
+		hash = h[:]
+	default:
+		return fmt.Errorf("%w: unsupported hash algorithm %q", ErrInvalidSignature, sig.HashAlgorithm)
+	}
+	var s ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	signed := append([]byte(sshSignatureMagic), ssh.Marshal(signedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          hash,
+	})...)
+	if err := pubkey.Verify(signed, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// decodeSSHArmor returns the binary SSH signature from its armored form,
+// without the magic preamble.
+func decodeSSHArmor(armored string) ([]byte, error) {
+	body, ok := strings.CutPrefix(strings.TrimSpace(armored), sshSignatureArmorStart)
+	if ok {
+		body, ok = strings.CutSuffix(body, sshSignatureArmorEnd)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed SSH signature armor", ErrInvalidSignature)
+	}
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	blob, ok = bytes.CutPrefix(blob, []byte(sshSignatureMagic))
+	if !ok {
+		return nil, fmt.Errorf("%w: missing SSH signature preamble", ErrInvalidSignature)
+	}
+	return blob, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/thediveo/gitrepofs/remote"
+)
+
+// Reasons for failed verifications, wrapped by [*VerificationError].
+var (
+	// ErrUnsigned indicates a missing signature.
+	ErrUnsigned = errors.New("unsigned")
+	// ErrUnknownKey indicates a signature by a key that isn't trusted, or
+	// isn't valid at the time of the signed object.
+	ErrUnknownKey = errors.New("signed by unknown key")
+	// ErrInvalidSignature indicates a malformed signature, a signature of an
+	// unsupported type, or a signature not matching the signed object.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// VerificationError is returned when verifying the signature of a commit or
+// annotated tag fails.
+type VerificationError struct {
+	// Type of the object, either a commit or a tag object.
+	Type plumbing.ObjectType
+	// Hash of the object.
+	Hash plumbing.Hash
+	// Err is the reason, wrapping either [ErrUnsigned], [ErrUnknownKey], or
+	// [ErrInvalidSignature].
+	Err error
+}
+
+// Error returns a textual description of the failed verification.
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%s %s failed verification: %s", e.Type, e.Hash, e.Err)
+}
+
+// Unwrap returns the reason of the failed verification.
+func (e *VerificationError) Unwrap() error { return e.Err }
+
+// Verifier verifies the signatures of commits and annotated tags against
+// trusted OpenPGP or SSH keys.
+type Verifier struct {
+	keyring openpgp.EntityList
+	signers []allowedSigner
+}
+
+var _ remote.Verifier = (*Verifier)(nil)
+
+// NewOpenPGPVerifier returns a [Verifier] accepting OpenPGP signatures by the
+// keys in the specified ASCII-armored key ring, such as exported using “gpg
+// --export --armor”.
+func NewOpenPGPVerifier(armoredKeyRing []byte) (*Verifier, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armoredKeyRing))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenPGP key ring, reason: %w", err)
+	}
+	return &Verifier{keyring: keyring}, nil
+}
+
+// NewSSHVerifier returns a [Verifier] accepting SSH signatures by the keys in
+// the specified “allowed signers” file, as used by git's
+// “gpg.ssh.allowedSignersFile” configuration. Keys restricted to namespaces
+// other than “git” are ignored. Keys with “valid-after” and “valid-before”
+// options are accepted only for commits and tags with their committer and
+// tagger times, respectively, inside the validity interval, as does git.
+func NewSSHVerifier(allowedSigners []byte) (*Verifier, error) {
+	signers, err := parseAllowedSigners(allowedSigners)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{signers: signers}, nil
+}
+
+// VerifyCommit returns nil if the commit carries a valid signature by a
+// trusted key, otherwise a [*VerificationError].
+func (v *Verifier) VerifyCommit(commit *object.Commit) error {
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return &VerificationError{Type: plumbing.CommitObject, Hash: commit.Hash, Err: err}
+	}
+	return v.verify(plumbing.CommitObject, commit.Hash, encoded, commit.PGPSignature, commit.Committer.When)
+}
+
+// VerifyTag returns nil if the annotated tag carries a valid signature by a
+// trusted key, otherwise a [*VerificationError].
+func (v *Verifier) VerifyTag(tag *object.Tag) error {
+	encoded := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(encoded); err != nil {
+		return &VerificationError{Type: plumbing.TagObject, Hash: tag.Hash, Err: err}
+	}
+	return v.verify(plumbing.TagObject, tag.Hash, encoded, tag.PGPSignature, tag.Tagger.When)
+}
+
+// verify checks the armored signature of the encoded object, created at the
+// specified time.
+func (v *Verifier) verify(typ plumbing.ObjectType, hash plumbing.Hash, encoded *plumbing.MemoryObject, signature string, when time.Time) error {
+	fail := func(err error) error {
+		return &VerificationError{Type: typ, Hash: hash, Err: err}
+	}
+	if signature == "" {
+		return fail(ErrUnsigned)
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return fail(err)
+	}
+	defer func() { _ = r.Close() }()
+	switch {
+	case strings.HasPrefix(signature, "-----BEGIN PGP SIGNATURE-----"):
+		if len(v.keyring) == 0 {
+			return fail(ErrUnknownKey)
+		}
+		_, err := openpgp.CheckArmoredDetachedSignature(
+			v.keyring, r, strings.NewReader(signature), nil)
+		if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+			return fail(ErrUnknownKey)
+		}
+		if err != nil {
+			return fail(fmt.Errorf("%w: %s", ErrInvalidSignature, err))
+		}
+		return nil
+	case strings.HasPrefix(signature, sshSignatureArmorStart):
+		message, err := io.ReadAll(r)
+		if err != nil {
+			return fail(err)
+		}
+		if err := verifySSHSignature(v.signers, message, signature, when); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+	return fail(fmt.Errorf("%w: unsupported signature type", ErrInvalidSignature))
+}
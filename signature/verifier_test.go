@@ -0,0 +1,179 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/thediveo/gitrepofs/test/localremote"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("signature verification", Ordered, func() {
+
+	var signed localremote.SignedRepo
+	var repo *git.Repository
+
+	BeforeAll(func(ctx context.Context) {
+		signed = localremote.CreateTransientSignedRepo()
+		repo = Successful(git.CloneContext(ctx, memory.NewStorage(), nil,
+			&git.CloneOptions{URL: signed.Path}))
+	})
+
+	commitOf := func(tag string) *object.Commit {
+		GinkgoHelper()
+		ref := Successful(repo.Tag(tag))
+		if tagobj, err := repo.TagObject(ref.Hash()); err == nil {
+			return Successful(tagobj.Commit())
+		}
+		return Successful(repo.CommitObject(ref.Hash()))
+	}
+
+	tagOf := func(tag string) *object.Tag {
+		GinkgoHelper()
+		return Successful(repo.TagObject(Successful(repo.Tag(tag)).Hash()))
+	}
+
+	// otherKeys returns an OpenPGP key ring and an allowed signers file with
+	// freshly generated keys not having signed anything.
+	otherKeys := func() ([]byte, []byte) {
+		GinkgoHelper()
+		entity := Successful(openpgp.NewEntity("Stranger", "", "stranger@example.org", nil))
+		var keyring bytes.Buffer
+		w := Successful(armor.Encode(&keyring, openpgp.PublicKeyType, nil))
+		Expect(entity.Serialize(w)).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+		pub, _ := Successful2R(ed25519.GenerateKey(rand.Reader))
+		sshPub := Successful(ssh.NewPublicKey(pub))
+		return keyring.Bytes(), append([]byte("stranger@example.org "), ssh.MarshalAuthorizedKey(sshPub)...)
+	}
+
+	reason := func(err error) error {
+		GinkgoHelper()
+		var verr *VerificationError
+		Expect(errors.As(err, &verr)).To(BeTrue(), "not a verification error: %v", err)
+		return verr.Err
+	}
+
+	It("verifies OpenPGP signatures", func() {
+		v := Successful(NewOpenPGPVerifier(signed.OpenPGPKeyRing))
+		Expect(v.VerifyCommit(commitOf("v0.3.0"))).To(Succeed())
+		Expect(v.VerifyTag(tagOf("v1.0.0"))).To(Succeed())
+	})
+
+	It("verifies SSH signatures", func() {
+		v := Successful(NewSSHVerifier(signed.AllowedSigners))
+		Expect(v.VerifyCommit(commitOf("v0.2.0"))).To(Succeed())
+	})
+
+	It("rejects unsigned objects", func() {
+		v := Successful(NewOpenPGPVerifier(signed.OpenPGPKeyRing))
+		err := v.VerifyCommit(commitOf("v0.1.0"))
+		Expect(reason(err)).To(Equal(ErrUnsigned))
+		Expect(err).To(MatchError(ContainSubstring("commit " + commitOf("v0.1.0").Hash.String())))
+		err = v.VerifyTag(tagOf("unsigned-v1.0.0"))
+		Expect(reason(err)).To(Equal(ErrUnsigned))
+		Expect(err.(*VerificationError).Type).To(Equal(plumbing.TagObject))
+	})
+
+	It("rejects unknown keys", func() {
+		keyring, allowedSigners := otherKeys()
+		pgp := Successful(NewOpenPGPVerifier(keyring))
+		Expect(reason(pgp.VerifyCommit(commitOf("v0.3.0")))).To(Equal(ErrUnknownKey))
+		Expect(reason(pgp.VerifyTag(tagOf("v1.0.0")))).To(Equal(ErrUnknownKey))
+		Expect(reason(pgp.VerifyCommit(commitOf("v0.2.0")))).To(Equal(ErrUnknownKey))
+
+		ssh := Successful(NewSSHVerifier(allowedSigners))
+		Expect(reason(ssh.VerifyCommit(commitOf("v0.2.0")))).To(Equal(ErrUnknownKey))
+		Expect(reason(ssh.VerifyCommit(commitOf("v0.3.0")))).To(Equal(ErrUnknownKey))
+	})
+
+	It("rejects tampered objects", func() {
+		commit := *commitOf("v0.2.0")
+		commit.Message = "tampered"
+		v := Successful(NewSSHVerifier(signed.AllowedSigners))
+		Expect(reason(v.VerifyCommit(&commit))).To(MatchError(ErrInvalidSignature))
+
+		commit = *commitOf("v0.3.0")
+		commit.Message = "tampered"
+		v = Successful(NewOpenPGPVerifier(signed.OpenPGPKeyRing))
+		Expect(reason(v.VerifyCommit(&commit))).To(MatchError(ErrInvalidSignature))
+
+		commit.PGPSignature = "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+		Expect(reason(v.VerifyCommit(&commit))).To(MatchError(ErrInvalidSignature))
+		commit.PGPSignature = "-----BEGIN SIGNED MESSAGE-----\n"
+		Expect(reason(v.VerifyCommit(&commit))).To(MatchError(ErrInvalidSignature))
+	})
+
+	It("honors allowed signer restrictions", func() {
+		Expect(Successful(NewSSHVerifier([]byte("# nobody\n\n"))).signers).To(BeEmpty())
+		for _, options := range []string{`namespaces="file" `, `cert-authority `} {
+			v := Successful(NewSSHVerifier(append([]byte("brian@palace.herodes "+options),
+				bytes.TrimPrefix(signed.AllowedSigners, []byte("brian@palace.herodes "))...)))
+			Expect(reason(v.VerifyCommit(commitOf("v0.2.0")))).To(Equal(ErrUnknownKey))
+		}
+		v := Successful(NewSSHVerifier(append([]byte(`brian@palace.herodes namespaces="file,git" `),
+			bytes.TrimPrefix(signed.AllowedSigners, []byte("brian@palace.herodes "))...)))
+		Expect(v.VerifyCommit(commitOf("v0.2.0"))).To(Succeed())
+	})
+
+	DescribeTable("honors allowed signer validity intervals",
+		func(options string, valid bool) {
+			v := Successful(NewSSHVerifier(append([]byte("brian@palace.herodes "+options+" "),
+				bytes.TrimPrefix(signed.AllowedSigners, []byte("brian@palace.herodes "))...)))
+			err := v.VerifyCommit(commitOf("v0.2.0"))
+			if valid {
+				Expect(err).To(Succeed())
+				return
+			}
+			Expect(reason(err)).To(Equal(ErrUnknownKey))
+		},
+		Entry("expired", `valid-before="20000101"`, false),
+		Entry("not yet valid", `valid-after="29990101Z"`, false),
+		Entry("valid after", `valid-after="200001010000"`, true),
+		Entry("valid before", `valid-before="29991231235959Z"`, true),
+		Entry("inside interval", `valid-after="20000101",valid-before="29990101"`, true),
+		Entry("outside interval", `valid-after="20000101",valid-before="20000102"`, false),
+	)
+
+	It("rejects invalid validity intervals", func() {
+		for _, options := range []string{`valid-after="2000"`, `valid-before="20001301"`, `valid-after="tomorrow"`} {
+			Expect(NewSSHVerifier(append([]byte("brian@palace.herodes "+options+" "),
+				bytes.TrimPrefix(signed.AllowedSigners, []byte("brian@palace.herodes "))...))).Error().To(
+				MatchError(ContainSubstring("invalid allowed signer in line 1")))
+		}
+	})
+
+	It("rejects invalid keys", func() {
+		Expect(NewOpenPGPVerifier([]byte("foobar"))).Error().To(HaveOccurred())
+		Expect(NewSSHVerifier([]byte("foo@example.org ssh-ed25519 foobar"))).Error().To(
+			MatchError(ContainSubstring("invalid allowed signer in line 1")))
+	})
+
+})
@@ -15,16 +15,27 @@
 package localremote
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
 	"embed"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
 
 	. "github.com/onsi/ginkgo/v2"   //nolint:staticcheck // we don't care about dot-imports
 	. "github.com/onsi/gomega"      //nolint:staticcheck // we don't care about dot-imports
@@ -39,12 +50,16 @@ var (
 
 const gitDir = ".git"
 
-var commitOptions = &git.CommitOptions{
-	Author: &object.Signature{
-		Name:  "Brian",
-		Email: "brian@palace.herodes",
-		When:  time.Now(),
-	},
+// commitOptions returns fresh commit options for each commit, as go-git
+// modifies the passed commit options, such as by filling in the parents.
+func commitOptions() *git.CommitOptions {
+	return &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "Brian",
+			Email: "brian@palace.herodes",
+			When:  time.Now(),
+		},
+	}
 }
 
 //go:embed files
@@ -69,8 +84,16 @@ func CreateTransientTestRepo() (repopath string) {
 
 	Expect(copyFile("README", path.Join(tmpdir, "README"), fileMode)).To(Succeed())
 	Expect(worktree.Add("README")).Error().NotTo(HaveOccurred())
-	commit := Successful(worktree.Commit("initial check-in", commitOptions))
+	commit := Successful(worktree.Commit("initial check-in", commitOptions()))
 	Expect(repo.CreateTag("v1.0", commit, nil)).Error().NotTo(HaveOccurred())
+	Expect(repo.CreateTag("v1.0.1", commit, &git.CreateTagOptions{
+		Tagger:  commitOptions().Author,
+		Message: "annotated re-release",
+	})).Error().NotTo(HaveOccurred())
+	for _, name := range []string{"cal-24.9.1", "release-20231224", "build-9"} {
+		Expect(repo.CreateTag(name, commit, nil)).Error().NotTo(HaveOccurred())
+	}
+	createBranch(repo, "release-1.0", commit)
 
 	Expect(os.Mkdir(path.Join(tmpdir, "fodder"), dirMode)).Error().NotTo(HaveOccurred())
 	Expect(copyFile("fodder/empty", path.Join(tmpdir, "fodder/empty"), fileMode)).To(Succeed())
@@ -80,12 +103,303 @@ func CreateTransientTestRepo() (repopath string) {
 	Expect(copyFile("folder/subfolder/canary.txt", path.Join(tmpdir, "folder/subfolder/canary.txt"), fileMode)).To(Succeed())
 	Expect(copyFile("folder/subfolder/schkript.sh", path.Join(tmpdir, "folder/subfolder/schkript.sh"), exeMode)).To(Succeed())
 	Expect(worktree.Add("folder")).Error().NotTo(HaveOccurred())
-	commit = Successful(worktree.Commit("adds canary", commitOptions))
+	commit = Successful(worktree.Commit("adds canary", commitOptions()))
 	Expect(repo.CreateTag("v1.1.1", commit, nil)).Error().NotTo(HaveOccurred())
+	for _, name := range []string{"cal-24.10.0", "release-20240301", "build-10"} {
+		Expect(repo.CreateTag(name, commit, nil)).Error().NotTo(HaveOccurred())
+	}
+	createBranch(repo, "release-1.1", commit)
+	createBranch(repo, "stable/1.x", commit)
+
+	// Please note that the embedded files cannot be named ".gitattributes", as
+	// go:embed would skip them, so we rename them only when copying.
+	Expect(copyFile("gitattributes", path.Join(tmpdir, ".gitattributes"), fileMode)).To(Succeed())
+	Expect(worktree.Add(".gitattributes")).Error().NotTo(HaveOccurred())
+	Expect(os.Mkdir(path.Join(tmpdir, "text"), dirMode)).Error().NotTo(HaveOccurred())
+	Expect(copyFile("text/gitattributes", path.Join(tmpdir, "text/.gitattributes"), fileMode)).To(Succeed())
+	for _, name := range []string{"auto.txt", "blob.dat", "crlf.txt", "utf16.txt"} {
+		Expect(copyFile("text/"+name, path.Join(tmpdir, "text", name), fileMode)).To(Succeed())
+	}
+	Expect(worktree.Add("text")).Error().NotTo(HaveOccurred())
+	Successful(worktree.Commit("adds text attributes", commitOptions()))
+
+	Expect(copyFile("VERSION", path.Join(tmpdir, "VERSION"), fileMode)).To(Succeed())
+	Expect(worktree.Add("VERSION")).Error().NotTo(HaveOccurred())
+	Successful(worktree.Commit("adds export attributes\n\nVERSION gets substituted.\n", commitOptions()))
+
+	Expect(os.Symlink("canary.txt", path.Join(tmpdir, "folder/subfolder/link"))).To(Succeed())
+	Expect(worktree.Add("folder/subfolder/link")).Error().NotTo(HaveOccurred())
+	commit = Successful(worktree.Commit("adds symbolic link", commitOptions()))
+	Expect(repo.CreateTag("v2.0.0-rc1", commit, nil)).Error().NotTo(HaveOccurred())
+	createBranch(repo, "stable/2.x", commit)
+
+	return tmpdir
+}
+
+// createBranch creates a branch with the specified name, pointing to the
+// specified commit.
+func createBranch(repo *git.Repository, name string, commit plumbing.Hash) {
+	GinkgoHelper()
+	Expect(repo.Storer.SetReference(
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), commit))).To(Succeed())
+}
+
+// CreateTransientModuleRepo initializes and populates a fresh git repository
+// with Go modules in subdirectories in a new temporary directory and then
+// returns the path to this newly created directory. The modules and their
+// version tags are:
+//   - the root module without go.mod: v0.1.0.
+//   - "sub/module": v1.0.0 and v2.0.0 (both without go.mod), v1.1.0 and
+//     v1.2.0-rc1 (with go.mod).
+//   - "sub/module/v2" in its major version subdirectory: v2.1.0.
+//   - "sub/module/v3" in "sub/module" on the major branch: v3.0.0.
+//   - "other" without go.mod: v1.0.0 and v3.0.0.
+//
+// Finally, the default branch retracts v3.0.0 as well as v2.0.0 to v2.1.0 in
+// a "RETRACTED" file, and v3.0.0 in "sub/module/go.mod".
+func CreateTransientModuleRepo() (repopath string) {
+	By("creating a temporary directory to initialize a new git repository in")
+	tmpdir := Successful(os.MkdirTemp("", "localremote-*"))
+	DeferCleanup(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	By("initializing git repository")
+	repo := Successful(git.PlainInit(tmpdir, false))
+	worktree := Successful(repo.Worktree())
+
+	// Please note that the go.mod files cannot be embedded, as directories
+	// with go.mod files belong to separate modules, so we write them directly.
+	writeFiles := func(files map[string]string) {
+		for name, contents := range files {
+			Expect(os.MkdirAll(path.Join(tmpdir, path.Dir(name)), dirMode)).To(Succeed())
+			Expect(os.WriteFile(path.Join(tmpdir, name), []byte(contents), fileMode)).To(Succeed())
+			Expect(worktree.Add(name)).Error().NotTo(HaveOccurred())
+		}
+	}
+	tag := func(commit plumbing.Hash, names ...string) {
+		for _, name := range names {
+			Expect(repo.CreateTag(name, commit, nil)).Error().NotTo(HaveOccurred())
+		}
+	}
+
+	By("checking in and tagging modules")
+	writeFiles(map[string]string{
+		"README":               "monorepo\n",
+		"sub/module/module.go": "package module\n",
+		"other/other.go":       "package other\n",
+	})
+	commit := Successful(worktree.Commit("initial check-in", commitOptions()))
+	tag(commit, "v0.1.0", "sub/module/v1.0.0", "sub/module/v2.0.0", "other/v1.0.0", "other/v3.0.0")
+
+	writeFiles(map[string]string{
+		"sub/module/go.mod": "module example.org/mono/sub/module\n",
+	})
+	commit = Successful(worktree.Commit("adds go.mod", commitOptions()))
+	tag(commit, "sub/module/v1.1.0", "sub/module/v1.2.0-rc1")
+
+	writeFiles(map[string]string{
+		"sub/module/v2/go.mod":    "module example.org/mono/sub/module/v2\n",
+		"sub/module/v2/module.go": "package module\n",
+	})
+	commit = Successful(worktree.Commit("adds v2 major version subdirectory", commitOptions()))
+	tag(commit, "sub/module/v2.1.0")
+
+	writeFiles(map[string]string{
+		"sub/module/go.mod": "module example.org/mono/sub/module/v3\n",
+	})
+	commit = Successful(worktree.Commit("switches to v3 major branch", commitOptions()))
+	tag(commit, "sub/module/v3.0.0")
+
+	writeFiles(map[string]string{
+		"RETRACTED":         "# retracted releases\nv3.0.0 # oops\n[v2.0.0, v2.1.0]\n",
+		"sub/module/go.mod": "module example.org/mono/sub/module/v3\n\nretract v3.0.0 // oops\n",
+	})
+	Successful(worktree.Commit("retracts releases", commitOptions()))
 
 	return tmpdir
 }
 
+// HistoryEpoch is the author and committer time of the first commit in the
+// repository created by [CreateTransientHistoryRepo]; each further commit
+// follows one hour after its predecessor.
+var HistoryEpoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// CreateTransientHistoryRepo initializes and populates a fresh git repository
+// with a linear history of edits by different authors in a new temporary
+// directory and then returns the path to this newly created directory. The
+// commits are:
+//  1. Alice adds "notes.txt" ("alpha", "beta", "gamma") and "other.txt".
+//  2. Bob changes "beta" to "BETA" in "notes.txt".
+//  3. Alice appends "more" to "other.txt".
+//  4. Carol renames "notes.txt" to "docs/notes.md" without changing it.
+//  5. Bob appends "delta" to "docs/notes.md".
+func CreateTransientHistoryRepo() (repopath string) {
+	By("creating a temporary directory to initialize a new git repository in")
+	tmpdir := Successful(os.MkdirTemp("", "localremote-*"))
+	DeferCleanup(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	By("initializing git repository")
+	repo := Successful(git.PlainInit(tmpdir, false))
+	worktree := Successful(repo.Worktree())
+
+	writeFiles := func(files map[string]string) {
+		for name, contents := range files {
+			Expect(os.MkdirAll(path.Join(tmpdir, path.Dir(name)), dirMode)).To(Succeed())
+			Expect(os.WriteFile(path.Join(tmpdir, name), []byte(contents), fileMode)).To(Succeed())
+			Expect(worktree.Add(name)).Error().NotTo(HaveOccurred())
+		}
+	}
+	when := HistoryEpoch
+	commit := func(msg string, author string) {
+		Successful(worktree.Commit(msg, &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  author,
+				Email: strings.ToLower(author) + "@example.org",
+				When:  when,
+			},
+		}))
+		when = when.Add(time.Hour)
+	}
+
+	By("checking in a history of edits")
+	writeFiles(map[string]string{
+		"notes.txt": "alpha\nbeta\ngamma\n",
+		"other.txt": "other\n",
+	})
+	commit("adds notes", "Alice")
+
+	writeFiles(map[string]string{"notes.txt": "alpha\nBETA\ngamma\n"})
+	commit("emphasizes beta", "Bob")
+
+	writeFiles(map[string]string{"other.txt": "other\nmore\n"})
+	commit("adds more to other", "Alice")
+
+	Expect(os.Mkdir(path.Join(tmpdir, "docs"), dirMode)).To(Succeed())
+	Successful(worktree.Move("notes.txt", "docs/notes.md"))
+	commit("moves notes into docs", "Carol")
+
+	writeFiles(map[string]string{"docs/notes.md": "alpha\nBETA\ngamma\ndelta\n"})
+	commit("adds delta", "Bob")
+
+	return tmpdir
+}
+
+// SignedRepo describes a repository created by [CreateTransientSignedRepo].
+type SignedRepo struct {
+	// Path to the repository.
+	Path string
+	// OpenPGPKeyRing is the armored public OpenPGP key ring of the signer.
+	OpenPGPKeyRing []byte
+	// AllowedSigners is the SSH allowed signers file for the signer.
+	AllowedSigners []byte
+}
+
+// CreateTransientSignedRepo initializes and populates a fresh git repository
+// with signed commits and tags in a new temporary directory, using freshly
+// generated OpenPGP and SSH keys. The tags are:
+//   - v0.1.0: lightweight tag of an unsigned commit.
+//   - v0.2.0: lightweight tag of an SSH-signed commit.
+//   - v0.3.0: lightweight tag of an OpenPGP-signed commit.
+//   - v1.0.0: OpenPGP-signed annotated tag of the same commit as v0.3.0.
+//   - unsigned-v1.0.0: unsigned annotated tag of the same commit as v0.3.0.
+func CreateTransientSignedRepo() SignedRepo {
+	By("generating signing keys")
+	pgpEntity := Successful(openpgp.NewEntity("Brian", "", "brian@palace.herodes", nil))
+	var keyring bytes.Buffer
+	w := Successful(armor.Encode(&keyring, openpgp.PublicKeyType, nil))
+	Expect(pgpEntity.Serialize(w)).To(Succeed())
+	Expect(w.Close()).To(Succeed())
+
+	_, sshKey := Successful2R(ed25519.GenerateKey(rand.Reader))
+	sshSigner := Successful(ssh.NewSignerFromKey(sshKey))
+
+	By("creating a temporary directory to initialize a new git repository in")
+	tmpdir := Successful(os.MkdirTemp("", "localremote-*"))
+	DeferCleanup(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	By("initializing git repository")
+	repo := Successful(git.PlainInit(tmpdir, false))
+	worktree := Successful(repo.Worktree())
+
+	By("checking in and tagging signed stuff")
+	commit := func(name string, contents string, sign func(*git.CommitOptions)) plumbing.Hash {
+		Expect(os.WriteFile(path.Join(tmpdir, name), []byte(contents), fileMode)).To(Succeed())
+		Expect(worktree.Add(name)).Error().NotTo(HaveOccurred())
+		opts := commitOptions()
+		sign(opts)
+		return Successful(worktree.Commit("adds "+name, opts))
+	}
+	hash := commit("README", "signed stuff\n", func(*git.CommitOptions) {})
+	Expect(repo.CreateTag("v0.1.0", hash, nil)).Error().NotTo(HaveOccurred())
+	hash = commit("ssh.txt", "SSH\n", func(o *git.CommitOptions) { o.Signer = sshGitSigner{sshSigner} })
+	Expect(repo.CreateTag("v0.2.0", hash, nil)).Error().NotTo(HaveOccurred())
+	hash = commit("pgp.txt", "OpenPGP\n", func(o *git.CommitOptions) { o.SignKey = pgpEntity })
+	Expect(repo.CreateTag("v0.3.0", hash, nil)).Error().NotTo(HaveOccurred())
+	Expect(repo.CreateTag("v1.0.0", hash, &git.CreateTagOptions{
+		Tagger:  commitOptions().Author,
+		Message: "signed release",
+		SignKey: pgpEntity,
+	})).Error().NotTo(HaveOccurred())
+	Expect(repo.CreateTag("unsigned-v1.0.0", hash, &git.CreateTagOptions{
+		Tagger:  commitOptions().Author,
+		Message: "unsigned release",
+	})).Error().NotTo(HaveOccurred())
+
+	return SignedRepo{
+		Path:           tmpdir,
+		OpenPGPKeyRing: keyring.Bytes(),
+		AllowedSigners: append([]byte("brian@palace.herodes "),
+			ssh.MarshalAuthorizedKey(sshSigner.PublicKey())...),
+	}
+}
+
+// sshGitSigner signs git objects with SSH signatures in the “git” namespace,
+// as “git commit -S” does when configured with “gpg.format=ssh”.
+type sshGitSigner struct {
+	signer ssh.Signer
+}
+
+// Sign returns the armored SSH signature of the message.
+func (s sshGitSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha512.Sum512(data)
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      []byte
+		HashAlgorithm string
+		Hash          []byte
+	}{"git", nil, "sha512", hash[:]})...)
+	sig, err := s.signer.Sign(rand.Reader, signed)
+	if err != nil {
+		return nil, err
+	}
+	blob := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      []byte
+		HashAlgorithm string
+		Signature     []byte
+	}{1, s.signer.PublicKey().Marshal(), "git", nil, "sha512", ssh.Marshal(sig)})...)
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var armored strings.Builder
+	armored.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		armored.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	armored.WriteString(encoded + "\n-----END SSH SIGNATURE-----\n")
+	return []byte(armored.String()), nil
+}
+
 func copyFile(from, to string, mode fs.FileMode) error {
 	contents, err := contentfs.ReadFile(path.Join("files", from))
 	if err != nil {
@@ -16,10 +16,12 @@ package localremote
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -65,4 +67,24 @@ var _ = Describe("transient local 'remote' repository", func() {
 
 	})
 
+	It("creates a transient repository with history", func(ctx context.Context) {
+		tmpdir := CreateTransientHistoryRepo()
+		repo := Successful(git.CloneContext(ctx, memory.NewStorage(), nil,
+			&git.CloneOptions{URL: tmpdir}))
+		head := Successful(repo.Head())
+		commits := Successful(repo.Log(&git.LogOptions{From: head.Hash()}))
+		var authors []string
+		Expect(commits.ForEach(func(c *object.Commit) error {
+			authors = append(authors, c.Author.Name)
+			return nil
+		})).To(Succeed())
+		Expect(authors).To(HaveExactElements("Bob", "Carol", "Alice", "Bob", "Alice"))
+
+		commit := Successful(repo.CommitObject(head.Hash()))
+		Expect(commit.Author.When).To(BeTemporally("==", HistoryEpoch.Add(4*time.Hour)))
+		file := Successful(commit.File("docs/notes.md"))
+		Expect(file.Contents()).To(Equal("alpha\nBETA\ngamma\ndelta\n"))
+		Expect(commit.File("notes.txt")).Error().To(HaveOccurred())
+	})
+
 })
@@ -0,0 +1,247 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// crlfAction describes how to convert line endings when checking out a file
+// into the working tree, mirroring git's own “crlf_action”.
+type crlfAction int
+
+const (
+	crlfUndefined crlfAction = iota
+	crlfBinary               // never convert, such as "-text" or "binary".
+	crlfText                 // "text": always treat as text.
+	crlfTextInput            // "text eol=lf" or "crlf=input".
+	crlfTextCRLF             // "text eol=crlf".
+	crlfAuto                 // "text=auto".
+	crlfAutoInput            // "text=auto eol=lf".
+	crlfAutoCRLF             // "text=auto eol=crlf".
+)
+
+// WithTextConversion returns a view onto the same git tree, but applying the
+// “text”, “eol” (as well as the legacy “crlf”), and “working-tree-encoding”
+// git attributes when reading files, the same way as “git checkout” does. The
+// git attributes are taken from the .gitattributes files along each file's
+// path inside the tree.
+//
+// Files without any explicit end-of-line attribute are served with LF line
+// endings, as on a system with “core.autocrlf=false” and a native LF line
+// ending. Local git configuration is never taken into account.
+//
+// [FileInfo.Size] reports the size of the converted file contents.
+func (gfs *FS) WithTextConversion() *FS {
+	view := *gfs
+	view.textconv = true
+	return &view
+}
+
+// convertText converts the specified contents of the named file into their
+// working tree representation, based on the specified git attributes for this
+// file.
+func convertText(name string, contents []byte, attrs map[string]gitattributes.Attribute) ([]byte, error) {
+	action := crlfActionFor(attrs)
+	if willConvertLFToCRLF(contents, action) {
+		contents = lfToCRLF(contents)
+	}
+	enc, ok := attrs["working-tree-encoding"]
+	if !ok || !enc.IsValueSet() || isUTF8(enc.Value()) {
+		return contents, nil
+	}
+	e, err := workingTreeEncoding(enc.Value())
+	if err != nil {
+		return nil, err
+	}
+	converted, err := e.NewEncoder().Bytes(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q from UTF-8 to %s, reason: %w",
+			name, enc.Value(), err)
+	}
+	return converted, nil
+}
+
+// crlfActionFor determines the line-ending conversion action from the “text”,
+// “crlf”, and “eol” attributes.
+func crlfActionFor(attrs map[string]gitattributes.Attribute) crlfAction {
+	action := crlfUndefined
+	if text, ok := attrs["text"]; ok {
+		action = textCRLFAction(text)
+	}
+	if action == crlfUndefined {
+		if crlf, ok := attrs["crlf"]; ok {
+			action = textCRLFAction(crlf)
+		}
+	}
+	if action == crlfBinary {
+		return action
+	}
+	eol, ok := attrs["eol"]
+	if !ok || !eol.IsValueSet() {
+		return action
+	}
+	switch {
+	case action == crlfAuto && eol.Value() == "lf":
+		action = crlfAutoInput
+	case action == crlfAuto && eol.Value() == "crlf":
+		action = crlfAutoCRLF
+	case eol.Value() == "lf":
+		action = crlfTextInput
+	case eol.Value() == "crlf":
+		action = crlfTextCRLF
+	}
+	return action
+}
+
+// textCRLFAction returns the line-ending conversion action for either the
+// “text” or the legacy “crlf” attribute.
+func textCRLFAction(attr gitattributes.Attribute) crlfAction {
+	switch {
+	case attr.IsSet():
+		return crlfText
+	case attr.IsUnset():
+		return crlfBinary
+	case attr.IsValueSet() && attr.Value() == "input":
+		return crlfTextInput
+	case attr.IsValueSet() && attr.Value() == "auto":
+		return crlfAuto
+	}
+	return crlfUndefined
+}
+
+// textStats counts the interesting characters in some file contents, in order
+// to decide whether to convert line endings and whether the contents are
+// binary.
+type textStats struct {
+	nul, lonecr, lonelf, crlf int
+	printable, nonprintable   int
+}
+
+// gatherStats gathers statistics about line endings and (non) printable
+// characters.
+func gatherStats(b []byte) (stats textStats) {
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '\r':
+			if i+1 < len(b) && b[i+1] == '\n' {
+				stats.crlf++
+				i++
+			} else {
+				stats.lonecr++
+			}
+		case c == '\n':
+			stats.lonelf++
+		case c == 127:
+			stats.nonprintable++
+		case c < 32:
+			switch c {
+			case '\b', '\t', '\033', '\014':
+				stats.printable++
+			case 0:
+				stats.nul++
+				stats.nonprintable++
+			default:
+				stats.nonprintable++
+			}
+		default:
+			stats.printable++
+		}
+	}
+	// a DOS end-of-file marker doesn't count as non-printable.
+	if len(b) > 0 && b[len(b)-1] == '\032' {
+		stats.nonprintable--
+	}
+	return
+}
+
+// isBinary returns true if the statistics indicate binary contents, using the
+// same heuristics as git.
+func (s textStats) isBinary() bool {
+	return s.lonecr > 0 || s.nul > 0 || (s.printable>>7) < s.nonprintable
+}
+
+// willConvertLFToCRLF returns true if the contents need to have their line
+// endings converted from LF to CRLF when checked out.
+func willConvertLFToCRLF(b []byte, action crlfAction) bool {
+	if action != crlfTextCRLF && action != crlfAutoCRLF {
+		return false
+	}
+	stats := gatherStats(b)
+	if stats.lonelf == 0 {
+		return false
+	}
+	if action == crlfAutoCRLF {
+		if stats.lonecr > 0 || stats.crlf > 0 || stats.isBinary() {
+			return false
+		}
+	}
+	return true
+}
+
+// lfToCRLF converts all lone LFs into CRLFs.
+func lfToCRLF(b []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(b) + bytes.Count(b, []byte{'\n'}))
+	for i, c := range b {
+		if c == '\n' && (i == 0 || b[i-1] != '\r') {
+			buf.WriteByte('\r')
+		}
+		buf.WriteByte(c)
+	}
+	return buf.Bytes()
+}
+
+// isUTF8 returns true if the specified encoding name is UTF-8, which is the
+// encoding git assumes for blobs anyway.
+func isUTF8(name string) bool {
+	name = strings.ToUpper(name)
+	return name == "UTF-8" || name == "UTF8"
+}
+
+// workingTreeEncoding returns the encoding for the specified (iconv-style)
+// encoding name. The “UTF-16” and “UTF-32” encodings without an explicit
+// endianness are written big-endian with a byte order mark, the “-BOM”
+// variants write the byte order mark in the specified endianness.
+func workingTreeEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToUpper(name) {
+	case "UTF-16":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case "UTF-16BE-BOM":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case "UTF-16LE-BOM":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case "UTF-32":
+		return utf32.UTF32(utf32.BigEndian, utf32.UseBOM), nil
+	case "UTF-32BE-BOM":
+		return utf32.UTF32(utf32.BigEndian, utf32.UseBOM), nil
+	case "UTF-32LE-BOM":
+		return utf32.UTF32(utf32.LittleEndian, utf32.UseBOM), nil
+	}
+	e, err := ianaindex.IANA.Encoding(name)
+	if err != nil || e == nil {
+		return nil, fmt.Errorf("unsupported working-tree-encoding %q", name)
+	}
+	return e, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("text conversion", func() {
+
+	var gfs *FS
+
+	BeforeEach(func(ctx context.Context) {
+		gfs = Successful(NewForRevision(ctx, tmprepdir, "master"))
+	})
+
+	It("serves blobs unconverted by default", func() {
+		Expect(fs.ReadFile(gfs, "text/crlf.txt")).To(
+			Equal([]byte("first line\nsecond line\n")))
+	})
+
+	DescribeTable("converts line endings and encodings",
+		func(name string, expected string) {
+			cfs := gfs.WithTextConversion()
+			contents := Successful(fs.ReadFile(cfs, name))
+			Expect(string(contents)).To(Equal(expected))
+
+			fi := Successful(fs.Stat(cfs, name))
+			Expect(fi.Size()).To(Equal(int64(len(expected))))
+
+			var size int64 = -1
+			Expect(fs.WalkDir(cfs, ".", func(path string, d fs.DirEntry, err error) error {
+				if path == name {
+					size = Successful(d.Info()).Size()
+				}
+				return err
+			})).To(Succeed())
+			Expect(size).To(Equal(int64(len(expected))))
+		},
+		Entry("eol=crlf", "text/crlf.txt", "first line\r\nsecond line\r\n"),
+		Entry("text=auto with existing CRLFs", "text/auto.txt", "first line\r\nsecond line\n"),
+		Entry("binary overriding eol", "text/blob.dat", "not\nconverted\n"),
+		Entry("working-tree-encoding", "text/utf16.txt",
+			"\xff\xfeG\x00r\x00\xfc\x00\xdf\x00e\x00\n\x00"),
+		Entry("no attributes", "README", "This is a \"remote\" git repository for unit testing purposes.\n"),
+	)
+
+	It("leaves the original file system unconverted", func() {
+		_ = gfs.WithTextConversion()
+		Expect(fs.ReadFile(gfs, "text/crlf.txt")).To(
+			Equal([]byte("first line\nsecond line\n")))
+	})
+
+})
+
+var _ = Describe("text conversion heuristics", func() {
+
+	DescribeTable("determines the line ending conversion action",
+		func(gitattrs string, expected crlfAction) {
+			stack := append(attributes{}, builtinMacros...)
+			stack = append(stack, mustParseAttributesLine("foo "+gitattrs))
+			Expect(crlfActionFor(stack.match("foo"))).To(Equal(expected))
+		},
+		Entry(nil, "", crlfUndefined),
+		Entry(nil, "text", crlfText),
+		Entry(nil, "-text", crlfBinary),
+		Entry(nil, "binary eol=crlf", crlfBinary),
+		Entry(nil, "text=auto", crlfAuto),
+		Entry(nil, "text=auto eol=lf", crlfAutoInput),
+		Entry(nil, "text=auto eol=crlf", crlfAutoCRLF),
+		Entry(nil, "eol=lf", crlfTextInput),
+		Entry(nil, "eol=crlf", crlfTextCRLF),
+		Entry(nil, "crlf=input", crlfTextInput),
+		Entry(nil, "-crlf", crlfBinary),
+	)
+
+	It("detects binary contents", func() {
+		Expect(gatherStats([]byte("foo\nbar\r\n")).isBinary()).To(BeFalse())
+		Expect(gatherStats([]byte("foo\x00bar")).isBinary()).To(BeTrue())
+		Expect(gatherStats([]byte("foo\rbar")).isBinary()).To(BeTrue())
+	})
+
+	It("doesn't convert binary contents automatically", func() {
+		Expect(willConvertLFToCRLF([]byte("foo\x00\n"), crlfAutoCRLF)).To(BeFalse())
+		Expect(willConvertLFToCRLF([]byte("foo\x00\n"), crlfTextCRLF)).To(BeTrue())
+		Expect(willConvertLFToCRLF([]byte("foo\n"), crlfTextInput)).To(BeFalse())
+	})
+
+	It("rejects unknown encodings", func() {
+		Expect(workingTreeEncoding("EBCDIC-FOOBAR")).Error().To(HaveOccurred())
+		Expect(workingTreeEncoding("ISO-8859-1")).NotTo(BeNil())
+	})
+
+})
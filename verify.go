@@ -0,0 +1,109 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// ErrHashMismatch indicates that the contents of a git object don't match the
+// object's hash.
+var ErrHashMismatch = errors.New("object contents don't match object hash")
+
+// Verify re-hashes the commit of the file system as well as all trees and blobs
+// of the file system's tree, checking that the objects in the repository
+// backing the file system haven't been tampered with. Verify checks all
+// objects, regardless of text conversion and archive views, but it doesn't
+// descend into submodules.
+//
+// If an object's contents don't match its hash, Verify returns an error of
+// type [*fs.PathError] with the Op field set to "verify", the Path field set
+// to the name of the file or directory, and the Err field wrapping
+// [ErrHashMismatch].
+func Verify(ctx context.Context, gfs *FS) error {
+	if gfs.commit != nil {
+		if err := gfs.verifyObject(plumbing.CommitObject, gfs.commit.Hash); err != nil {
+			return &fs.PathError{Op: "verify", Path: ".", Err: err}
+		}
+	}
+	seen := map[plumbing.Hash]bool{}
+	return gfs.verifyTree(ctx, ".", gfs.tree.Hash, seen)
+}
+
+// verifyTree re-hashes the specified tree as well as its subtrees and blobs,
+// skipping objects already seen.
+func (gfs *FS) verifyTree(ctx context.Context, dir string, hash plumbing.Hash, seen map[plumbing.Hash]bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := gfs.verifyObject(plumbing.TreeObject, hash); err != nil {
+		return &fs.PathError{Op: "verify", Path: dir, Err: err}
+	}
+	seen[hash] = true
+	tree, err := gfs.repo.TreeObject(hash)
+	if err != nil {
+		return &fs.PathError{Op: "verify", Path: dir, Err: err}
+	}
+	for _, entry := range tree.Entries {
+		if seen[entry.Hash] {
+			continue
+		}
+		name := path.Join(dir, entry.Name)
+		switch entry.Mode {
+		case filemode.Dir:
+			if err := gfs.verifyTree(ctx, name, entry.Hash, seen); err != nil {
+				return err
+			}
+		case filemode.Submodule:
+			continue
+		default:
+			if err := gfs.verifyObject(plumbing.BlobObject, entry.Hash); err != nil {
+				return &fs.PathError{Op: "verify", Path: name, Err: err}
+			}
+			seen[entry.Hash] = true
+		}
+	}
+	return nil
+}
+
+// verifyObject returns an error wrapping [ErrHashMismatch] if the contents of
+// the specified object don't match its hash.
+func (gfs *FS) verifyObject(objtype plumbing.ObjectType, hash plumbing.Hash) error {
+	obj, err := gfs.repo.Storer.EncodedObject(objtype, hash)
+	if err != nil {
+		return err
+	}
+	r, err := obj.Reader()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	hasher := plumbing.NewHasher(objtype, obj.Size())
+	if _, err := io.Copy(hasher, r); err != nil {
+		return err
+	}
+	if sum := hasher.Sum(); sum != hash {
+		return fmt.Errorf("%s %s hashes to %s: %w", objtype, hash, sum, ErrHashMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gitrepofs
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/thediveo/gitrepofs/version"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("content integrity", func() {
+
+	It("checks the expected tree", func(ctx context.Context) {
+		Expect(NewForRevision(ctx, tmprepdir, commit.Hash.String(),
+			WithExpectedTree(commit.TreeHash.String()))).Error().NotTo(HaveOccurred())
+		Expect(NewForRevision(ctx, tmprepdir, "master",
+			WithExpectedTree(commit.TreeHash.String()))).Error().To(MatchError(ErrTreeMismatch))
+		Expect(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher,
+			WithExpectedTree(commit.TreeHash.String()))).Error().NotTo(HaveOccurred())
+	})
+
+	DescribeTable("rejects malformed expected trees",
+		func(ctx context.Context, hash string) {
+			Expect(NewForRevision(ctx, tmprepdir, commit.Hash.String(), WithExpectedTree(hash))).
+				Error().To(MatchError(ErrInvalidHash))
+			Expect(OpenLatest(ctx, tmprepdir, version.SemverTagMatcher, WithExpectedTree(hash))).
+				Error().To(MatchError(ErrInvalidHash))
+		},
+		Entry("empty", ""),
+		Entry("version", "v1.2.3"),
+		Entry("leading space", " 0123abcd"),
+		Entry("prefixed", "sha1:0123"),
+		Entry("zero", "0000000000000000000000000000000000000000"),
+	)
+
+	It("verifies untampered objects", func(ctx context.Context) {
+		gfs := Successful(NewForRevision(ctx, tmprepdir, commit.Hash.String()))
+		Expect(Verify(ctx, gfs)).To(Succeed())
+		Expect(Verify(ctx, Successful(gfs.sub("folder")))).To(Succeed())
+	})
+
+	It("detects tampered objects", func(ctx context.Context) {
+		gfs := Successful(NewForRevision(ctx, tmprepdir, commit.Hash.String()))
+		entry := Successful(gfs.tree.FindEntry("folder/subfolder/canary.txt"))
+
+		tampered := &plumbing.MemoryObject{}
+		tampered.SetType(plumbing.BlobObject)
+		Expect(tampered.Write([]byte("tweet!\n"))).Error().NotTo(HaveOccurred())
+		storage := gfs.repo.Storer.(*memory.Storage)
+		storage.Objects[entry.Hash] = tampered
+		storage.Blobs[entry.Hash] = tampered
+
+		err := Verify(ctx, gfs)
+		Expect(err).To(MatchError(ErrHashMismatch))
+		Expect(err).To(BeAssignableToTypeOf(&fs.PathError{}))
+		Expect(err.(*fs.PathError).Path).To(Equal("folder/subfolder/canary.txt"))
+	})
+
+	It("cancels verification", func() {
+		gfs := Successful(NewForRevision(context.Background(), tmprepdir, commit.Hash.String()))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(Verify(ctx, gfs)).To(MatchError(context.Canceled))
+	})
+
+})
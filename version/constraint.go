@@ -0,0 +1,311 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thediveo/gitrepofs/remote"
+	"golang.org/x/mod/semver"
+)
+
+// Constraint restricts versions, such as in “>=1.2, <2” or “~1.4”. Use
+// [ParseConstraint] to create a Constraint from its textual representation.
+type Constraint struct {
+	expr         string
+	alternatives [][]versionPredicate // OR'ed groups of AND'ed predicates.
+}
+
+// versionPredicate returns true if the (valid) semver satisfies it.
+type versionPredicate func(semver string) bool
+
+// ParseConstraint parses a version constraint expression and returns the
+// corresponding Constraint. The expression consists of one or more
+// comparisons, separated by commas or spaces, which all must be satisfied.
+// Multiple such groups can be separated by “||”, where only one of the groups
+// needs to be satisfied.
+//
+// A comparison consists of an optional operator, followed by a (potentially
+// partial) version, such as “1”, “1.2”, “v1.2.3”, or “1.2.x”. Versions without
+// an operator or with the “=” operator match all versions with the specified
+// MAJOR, MAJOR.MINOR, or MAJOR.MINOR.PATCH.
+//   - “=1.2”, “1.2”, “1.2.x”, “1.2.*”: >=1.2.0, <1.3.0.
+//   - “!=1.2.3”: anything but 1.2.3.
+//   - “>1.2”: >=1.3.0; “>=1.2”: >=1.2.0.
+//   - “<1.2”: <1.2.0; “<=1.2”: <1.3.0.
+//   - “~1.4”, “~1.4.2”: >=1.4.0 or >=1.4.2, <1.5.0; “~1”: >=1.0.0, <2.0.0.
+//   - “^3.1”: >=3.1.0, <4.0.0; “^0.3.1”: >=0.3.1, <0.4.0; “^0.0.3”: >=0.0.3,
+//     <0.0.4.
+//   - “*”: any version.
+//
+// Upper bounds derived from partial versions exclude the pre-releases of the
+// upper bound, so “<2” doesn't match “v2.0.0-rc1”, while “<2.0.0” does.
+func ParseConstraint(expr string) (*Constraint, error) {
+	c := &Constraint{expr: expr}
+	for group := range strings.SplitSeq(expr, "||") {
+		preds, err := parseConstraintGroup(group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+		}
+		c.alternatives = append(c.alternatives, preds)
+	}
+	return c, nil
+}
+
+// MustParseConstraint is like [ParseConstraint], but panics if the constraint
+// expression cannot be parsed.
+func MustParseConstraint(expr string) *Constraint {
+	c, err := ParseConstraint(expr)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the original constraint expression.
+func (c *Constraint) String() string { return c.expr }
+
+// Check returns true if the specified semver satisfies the constraint. It
+// returns false for invalid semvers.
+func (c *Constraint) Check(version string) bool {
+	if !semver.IsValid(version) {
+		return false
+	}
+	for _, preds := range c.alternatives {
+		ok := true
+		for _, pred := range preds {
+			if !pred(version) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewConstrainedMatcher returns a VersionMatcherFn that only matches the
+// versions matched by fn that additionally satisfy the specified constraint.
+func NewConstrainedMatcher(fn VersionMatcherFn, c *Constraint) VersionMatcherFn {
+	return func(refname string) string {
+		version := fn(refname)
+		if version == "" || !c.Check(version) {
+			return ""
+		}
+		return version
+	}
+}
+
+// LatestConstrainedReleaseTag determines the latest release tag in the
+// specified remote git repository that matches the specified pattern and
+// additionally satisfies the specified version constraint, such as “>=1.2,
+// <2” (see [ParseConstraint] for details). For instance, use the constraint
+// “5.x” to get the latest 5.x release.
+func LatestConstrainedReleaseTag(ctx context.Context, remoteURL string, fn VersionMatcherFn, constraint string, opts ...remote.Option) (semanticver string, ref string, err error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", "", err
+	}
+	return LatestReleaseTag(ctx, remoteURL, NewConstrainedMatcher(fn, c), opts...)
+}
+
+// parseConstraintGroup parses a group of comparisons that all need to be
+// satisfied.
+func parseConstraintGroup(group string) ([]versionPredicate, error) {
+	fields := strings.FieldsFunc(group, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty comparison")
+	}
+	var preds []versionPredicate
+	for idx := 0; idx < len(fields); idx++ {
+		comparison := fields[idx]
+		// allow for whitespace between operators and versions
+		if strings.Trim(comparison, "=!<>~^") == "" {
+			if idx+1 >= len(fields) {
+				return nil, fmt.Errorf("missing version after operator %q", comparison)
+			}
+			idx++
+			comparison += fields[idx]
+		}
+		pred, err := parseComparison(comparison)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	return preds, nil
+}
+
+// operators lists the supported comparison operators; longer operators must
+// come before their shorter prefixes.
+var operators = []string{"!=", ">=", "<=", "=", ">", "<", "~", "^"}
+
+// parseComparison parses a single comparison, consisting of an optional
+// operator and a (partial) version.
+func parseComparison(comparison string) (versionPredicate, error) {
+	op := ""
+	for _, o := range operators {
+		if strings.HasPrefix(comparison, o) {
+			op = o
+			break
+		}
+	}
+	v, err := parsePartialVersion(comparison[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := v.lower(), v.upper()
+	if v.parts == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "~", "^":
+			return func(string) bool { return true }, nil
+		}
+		return nil, fmt.Errorf("operator %q cannot be used with wildcard version", op)
+	}
+	switch op {
+	case "", "=":
+		if v.parts == 3 {
+			return func(s string) bool { return semver.Compare(s, lo) == 0 }, nil
+		}
+		return between(lo, hi), nil
+	case "!=":
+		if v.parts == 3 {
+			return func(s string) bool { return semver.Compare(s, lo) != 0 }, nil
+		}
+		in := between(lo, hi)
+		return func(s string) bool { return !in(s) }, nil
+	case ">":
+		if v.parts == 3 {
+			return func(s string) bool { return semver.Compare(s, lo) > 0 }, nil
+		}
+		return func(s string) bool { return semver.Compare(s, hi) >= 0 }, nil
+	case ">=":
+		return func(s string) bool { return semver.Compare(s, lo) >= 0 }, nil
+	case "<":
+		if v.parts < 3 {
+			lo += "-0"
+		}
+		return func(s string) bool { return semver.Compare(s, lo) < 0 }, nil
+	case "<=":
+		if v.parts == 3 {
+			return func(s string) bool { return semver.Compare(s, lo) <= 0 }, nil
+		}
+		return func(s string) bool { return semver.Compare(s, hi) < 0 }, nil
+	case "~":
+		if v.parts == 1 {
+			return between(lo, hi), nil
+		}
+		return between(lo, versionString(v.major, v.minor+1, 0)+"-0"), nil
+	}
+	// "^": caret ranges allow changes that don't modify the left-most non-zero
+	// element.
+	switch {
+	case v.major > 0 || v.parts == 1:
+		hi = versionString(v.major+1, 0, 0) + "-0"
+	case v.minor > 0 || v.parts == 2:
+		hi = versionString(0, v.minor+1, 0) + "-0"
+	default:
+		hi = versionString(0, 0, v.patch+1) + "-0"
+	}
+	return between(lo, hi), nil
+}
+
+// between returns a predicate checking that a semver is within lo (inclusive)
+// and hi (exclusive).
+func between(lo, hi string) versionPredicate {
+	return func(s string) bool {
+		return semver.Compare(s, lo) >= 0 && semver.Compare(s, hi) < 0
+	}
+}
+
+// partialVersion is a version with only the first parts elements specified.
+type partialVersion struct {
+	major, minor, patch int
+	parts               int    // number of elements specified, 0 is "*".
+	suffix              string // pre-release and build, only for 3 parts.
+}
+
+// parsePartialVersion parses a partial version, optionally with a “v” prefix
+// and optionally with trailing “x”, “X”, or “*” wildcards.
+func parsePartialVersion(s string) (partialVersion, error) {
+	v := partialVersion{}
+	if s == "" {
+		return v, fmt.Errorf("missing version")
+	}
+	version := strings.TrimPrefix(s, "v")
+	core, suffix := version, ""
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		core, suffix = version[:i], version[i:]
+	}
+	elements := strings.Split(core, ".")
+	if len(elements) > 3 {
+		return v, fmt.Errorf("invalid version %q: too many elements", s)
+	}
+	nums := []*int{&v.major, &v.minor, &v.patch}
+	wildcard := false
+	for idx, element := range elements {
+		if element == "x" || element == "X" || element == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return v, fmt.Errorf("invalid version %q: number after wildcard", s)
+		}
+		n, err := strconv.Atoi(element)
+		if err != nil || n < 0 || (len(element) > 1 && element[0] == '0') {
+			return v, fmt.Errorf("invalid version %q: invalid number %q", s, element)
+		}
+		*nums[idx] = n
+		v.parts++
+	}
+	if suffix != "" {
+		if v.parts != 3 {
+			return v, fmt.Errorf("invalid version %q: pre-release or build requires MAJOR.MINOR.PATCH", s)
+		}
+		v.suffix = suffix
+		if !semver.IsValid(v.lower()) {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	return v, nil
+}
+
+// lower returns the lowest version matching the partial version.
+func (v partialVersion) lower() string {
+	return versionString(v.major, v.minor, v.patch) + v.suffix
+}
+
+// upper returns the (exclusive) upper version bound for the partial version,
+// excluding pre-releases of the upper bound itself.
+func (v partialVersion) upper() string {
+	switch v.parts {
+	case 1:
+		return versionString(v.major+1, 0, 0) + "-0"
+	case 2:
+		return versionString(v.major, v.minor+1, 0) + "-0"
+	}
+	return v.lower()
+}
+
+func versionString(major, minor, patch int) string {
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package version
+
+import (
+	"context"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("version constraints", func() {
+
+	DescribeTable("checks versions against constraints",
+		func(expr string, matching []string, notmatching []string) {
+			c := Successful(ParseConstraint(expr))
+			Expect(c.String()).To(Equal(expr))
+			for _, v := range matching {
+				Expect(c.Check(v)).To(BeTrue(), "%s should satisfy %s", v, expr)
+			}
+			for _, v := range notmatching {
+				Expect(c.Check(v)).To(BeFalse(), "%s should not satisfy %s", v, expr)
+			}
+		},
+		Entry(nil, "*", []string{"v0.0.1", "v42.0.0"}, []string{"foobar"}),
+		Entry(nil, "1.2", []string{"v1.2.0", "v1.2.99"}, []string{"v1.1.0", "v1.3.0", "v1.3.0-rc1"}),
+		Entry(nil, "=1.2.3", []string{"v1.2.3", "v1.2.3+build5"}, []string{"v1.2.4", "v1.2.3-rc1"}),
+		Entry(nil, "5.x", []string{"v5.0.0", "v5.9.9"}, []string{"v4.9.9", "v6.0.0", "v6.0.0-rc1"}),
+		Entry(nil, "1.2.*", []string{"v1.2.0", "v1.2.7"}, []string{"v1.3.0"}),
+		Entry(nil, "!=1.2.3", []string{"v1.2.2", "v1.2.4"}, []string{"v1.2.3"}),
+		Entry(nil, "!=1.2", []string{"v1.1.0", "v1.3.0"}, []string{"v1.2.0", "v1.2.5"}),
+		Entry(nil, ">1.2", []string{"v1.3.0", "v2.0.0"}, []string{"v1.2.9"}),
+		Entry(nil, ">1.2.3", []string{"v1.2.4"}, []string{"v1.2.3"}),
+		Entry(nil, "<=1.2", []string{"v1.2.9", "v1.0.0"}, []string{"v1.3.0"}),
+		Entry(nil, "<=1.2.3", []string{"v1.2.3"}, []string{"v1.2.4"}),
+		Entry(nil, ">=1.2, <2", []string{"v1.2.0", "v1.99.0"}, []string{"v1.1.9", "v2.0.0", "v2.0.0-rc1"}),
+		Entry(nil, ">= 1.2 < 2.0.0", []string{"v1.2.0", "v2.0.0-rc1"}, []string{"v2.0.0"}),
+		Entry(nil, "~1.4", []string{"v1.4.0", "v1.4.9"}, []string{"v1.3.9", "v1.5.0"}),
+		Entry(nil, "~1.4.2", []string{"v1.4.2", "v1.4.9"}, []string{"v1.4.1", "v1.5.0"}),
+		Entry(nil, "~1", []string{"v1.0.0", "v1.9.0"}, []string{"v2.0.0"}),
+		Entry(nil, "^3.1", []string{"v3.1.0", "v3.9.9"}, []string{"v3.0.9", "v4.0.0"}),
+		Entry(nil, "^0.3.1", []string{"v0.3.1", "v0.3.9"}, []string{"v0.3.0", "v0.4.0"}),
+		Entry(nil, "^0.0.3", []string{"v0.0.3"}, []string{"v0.0.4"}),
+		Entry(nil, "^0", []string{"v0.0.3", "v0.9.0"}, []string{"v1.0.0"}),
+		Entry(nil, "^v2.0.0-rc1", []string{"v2.0.0-rc1", "v2.0.0", "v2.1.0"}, []string{"v2.0.0-alpha", "v3.0.0"}),
+		Entry(nil, "1.x || >=3", []string{"v1.5.0", "v3.0.0"}, []string{"v2.0.0"}),
+	)
+
+	DescribeTable("rejects malformed constraints",
+		func(expr string, experr string) {
+			Expect(ParseConstraint(expr)).Error().To(MatchError(ContainSubstring(experr)))
+		},
+		Entry(nil, "", "empty comparison"),
+		Entry(nil, "1.x ||", "empty comparison"),
+		Entry(nil, ">=", `missing version after operator ">="`),
+		Entry(nil, ">=foo", `invalid number "foo"`),
+		Entry(nil, "1.2.3.4", "too many elements"),
+		Entry(nil, "1.x.3", "number after wildcard"),
+		Entry(nil, "01.2", `invalid number "01"`),
+		Entry(nil, "1.2-rc1", "requires MAJOR.MINOR.PATCH"),
+		Entry(nil, "1.2.3-rc..1", "invalid version"),
+		Entry(nil, ">*", "cannot be used with wildcard"),
+	)
+
+	It("panics on malformed constraints", func() {
+		Expect(func() { MustParseConstraint(">=") }).To(Panic())
+		Expect(MustParseConstraint(">=1")).NotTo(BeNil())
+	})
+
+	It("matches only constrained versions", func() {
+		ctm := NewConstrainedMatcher(SemverTagMatcher, MustParseConstraint("1.x"))
+		Expect(ctm("refs/tags/v1.2.3")).To(Equal("v1.2.3"))
+		Expect(ctm("refs/tags/v2.0.0")).To(BeEmpty())
+		Expect(ctm("refs/tags/foobar")).To(BeEmpty())
+	})
+
+	Context("with a remote repository", Ordered, func() {
+
+		var tmprepdir string
+
+		BeforeAll(func() {
+			tmprepdir = localremote.CreateTransientTestRepo()
+		})
+
+		It("finds the latest constrained version", func(ctx context.Context) {
+			semver, ref, err := LatestConstrainedReleaseTag(ctx, tmprepdir,
+				NewPrefixedTagMatcher("", WithPrereleases()), "<1.0.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(semver).To(Equal("v1.0"))
+			Expect(ref).To(Equal("refs/tags/v1.0"))
+		})
+
+		It("reports malformed constraints", func(ctx context.Context) {
+			Expect(LatestConstrainedReleaseTag(ctx, tmprepdir, SemverTagMatcher, "<<1")).
+				Error().To(HaveOccurred())
+		})
+
+	})
+
+})
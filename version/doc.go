@@ -22,5 +22,69 @@ In case the version tags feature a prefix, such as in "libfoo-1.6.66", use
 		context.Background(),
 		remoteURL,
 		NewPrefixedTagMatcher("libfoo-"))
+
+Pre-release versions, such as "v2.0.0-rc1", are ignored by default. Pass the
+[WithPrereleases] option to [NewPrefixedTagMatcher] in order to take them into
+account; pre-releases then come before their corresponding releases.
+
+In order to stay on a particular major or minor version line, use
+[LatestConstrainedReleaseTag] with a version constraint, such as "5.x", ">=1.2,
+<2", "~1.4", or "^3.1":
+
+	semver, ref, err := LatestConstrainedReleaseTag(
+		context.Background(),
+		remoteURL,
+		SemverTagMatcher,
+		"5.x")
+
+Go modules in repository subdirectories have version tags prefixed with their
+subdirectory, such as "sub/module/v1.2.3". Use [NewGoModuleTagMatcher] to match
+such tags following the Go module rules, including "/vN" major version
+suffixes and "+incompatible" versions:
+
+	semver, ref, err := LatestReleaseTag(
+		context.Background(),
+		remoteURL,
+		NewGoModuleTagMatcher("sub/module/v2"))
+
+Upstreams publishing release branches instead of tags, such as "release-1.4"
+or "stable/2.x", can be followed using [NewBranchMatcher]; [LatestRelease] then
+returns the latest release branch together with the commit hash of its head:
+
+	latest, err := LatestRelease(
+		context.Background(),
+		remoteURL,
+		NewBranchMatcher("stable/", ".x"))
+
+Upstreams not using semver can be handled by passing a version [Scheme] to
+[LatestReleaseTagForScheme], [LatestReleaseForScheme], or
+[ListReleaseTagsForScheme]. For instance, use [NewCalVerScheme] for calendar
+versions, such as "2024.03.1", [NewDateStampScheme] for date-stamped tags, such
+as "release-20240301", or [NewSequenceScheme] for numbered tags, such as
+"build-42":
+
+	version, ref, err := LatestReleaseTagForScheme(
+		context.Background(),
+		remoteURL,
+		NewDateStampScheme("release-", "20060102"))
+
+In order to skip yanked or retracted releases, wrap a scheme using [Retract] or
+[ExcludeVersions]. [RetractedVersions] reads the retracted versions from a file
+on the default branch of the remote repository, either a [RetractFilename] file
+listing the retracted versions, or the “retract” directives of a go.mod file:
+
+	retractions, err := RetractedVersions(
+		context.Background(),
+		remoteURL,
+		"go.mod")
+	scheme, err := Retract(SemverTagMatcher, retractions)
+	semver, ref, err := LatestReleaseTagForScheme(
+		context.Background(),
+		remoteURL,
+		scheme)
+
+Use [ListReleaseTags] to get all matching version references in semver order,
+together with their commit hashes, such as for generating changelogs or finding
+the previous release.
 */
 package version
@@ -0,0 +1,110 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// IncompatibleSuffix is the build metadata suffix that Go modules use for
+// versions v2 and later of modules without a matching major version suffix,
+// such as "v2.0.0+incompatible".
+const IncompatibleSuffix = "+incompatible"
+
+// SplitModuleDir splits the repository subdirectory of a Go module into the
+// directory its version tags are prefixed with and its major version suffix,
+// if any. For instance, "sub/module/v2" splits into "sub/module" and "/v2",
+// while "sub/module" splits into "sub/module" and "". The repository root
+// directory is "" (or "."), and "v2" splits into "" and "/v2".
+func SplitModuleDir(moduleDir string) (tagDir string, pathMajor string) {
+	moduleDir = strings.Trim(path.Clean("/"+moduleDir), "/")
+	tagDir, last := path.Split(moduleDir)
+	tagDir = strings.TrimSuffix(tagDir, "/")
+	if major := majorOf(last); major >= 2 {
+		return tagDir, "/" + last
+	}
+	return moduleDir, ""
+}
+
+// majorOf returns the major version number of a major version suffix element,
+// such as 2 for "v2", or -1 if the element isn't a valid major version suffix.
+func majorOf(element string) int {
+	digits, ok := strings.CutPrefix(element, "v")
+	if !ok || digits == "" || digits[0] == '0' {
+		return -1
+	}
+	major, err := strconv.Atoi(digits)
+	if err != nil || major < 0 {
+		return -1
+	}
+	return major
+}
+
+// NewGoModuleTagMatcher returns a VersionMatcherFn matching the version tags
+// of the Go module in the specified repository subdirectory, following the Go
+// module version tag rules:
+//   - tags of modules in subdirectories are prefixed with the subdirectory,
+//     such as "sub/module/v1.2.3", while tags of modules in the repository
+//     root directory have no prefix.
+//   - tags must be canonical semvers in the MAJOR.MINOR.PATCH format; tags
+//     with BUILD metadata are ignored.
+//   - for modules with a major version suffix "/vN", such as in
+//     "sub/module/v2", only tags with the same major version match, such as
+//     "sub/module/v2.1.0". The suffix is not part of the tag prefix.
+//   - for modules without a major version suffix, tags with major versions v0
+//     and v1 match as is, while tags with major versions v2 and later match as
+//     "+incompatible" versions, such as "v2.0.0+incompatible".
+//
+// Please note that whether an "+incompatible" version actually is a valid
+// version, or a version with a "/vN" major version suffix lives in a major
+// version subdirectory or in the tag directory, depends on the go.mod files
+// at the tagged commit; see [github.com/thediveo/gitrepofs.OpenGoModule].
+//
+// Pre-release versions are ignored, unless the [WithPrereleases] option has
+// been specified.
+func NewGoModuleTagMatcher(moduleDir string, opts ...MatcherOption) VersionMatcherFn {
+	o := matcherOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tagDir, pathMajor := SplitModuleDir(moduleDir)
+	prefix := "refs/tags/"
+	if tagDir != "" {
+		prefix += tagDir + "/"
+	}
+	return func(refname string) string {
+		version, ok := strings.CutPrefix(refname, prefix)
+		if !ok || !semver.IsValid(version) || semver.Canonical(version) != version {
+			return ""
+		}
+		if semver.Prerelease(version) != "" && !o.prereleases {
+			return ""
+		}
+		major := majorOf(semver.Major(version))
+		switch {
+		case pathMajor != "":
+			if "/"+semver.Major(version) != pathMajor {
+				return ""
+			}
+		case major >= 2:
+			version += IncompatibleSuffix
+		}
+		return version
+	}
+}
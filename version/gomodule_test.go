@@ -0,0 +1,83 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package version
+
+import (
+	"context"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("Go module version tags", func() {
+
+	DescribeTable("splits module directories",
+		func(moduleDir string, exptagdir string, exppathmajor string) {
+			tagDir, pathMajor := SplitModuleDir(moduleDir)
+			Expect(tagDir).To(Equal(exptagdir))
+			Expect(pathMajor).To(Equal(exppathmajor))
+		},
+		Entry(nil, "", "", ""),
+		Entry(nil, ".", "", ""),
+		Entry(nil, "v2", "", "/v2"),
+		Entry(nil, "sub/module", "sub/module", ""),
+		Entry(nil, "sub/module/", "sub/module", ""),
+		Entry(nil, "sub/module/v1", "sub/module/v1", ""),
+		Entry(nil, "sub/module/v02", "sub/module/v02", ""),
+		Entry(nil, "sub/module/v12", "sub/module", "/v12"),
+	)
+
+	DescribeTable("matches Go module version tags",
+		func(moduleDir string, refname string, expected string) {
+			Expect(NewGoModuleTagMatcher(moduleDir)(refname)).To(Equal(expected))
+		},
+		Entry(nil, "", "refs/tags/v1.2.3", "v1.2.3"),
+		Entry(nil, "", "refs/tags/v2.0.0", "v2.0.0+incompatible"),
+		Entry(nil, "", "refs/tags/sub/module/v1.2.3", ""),
+		Entry(nil, "v2", "refs/tags/v2.0.0", "v2.0.0"),
+		Entry(nil, "v2", "refs/tags/v1.2.3", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v1.2.3", "v1.2.3"),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v0.0.1", "v0.0.1"),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v3.0.0", "v3.0.0+incompatible"),
+		Entry(nil, "sub/module", "refs/tags/v1.2.3", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/nested/v1.2.3", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v1.2", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/1.2.3", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v1.2.3+build5", ""),
+		Entry(nil, "sub/module", "refs/tags/sub/module/v1.3.0-rc1", ""),
+		Entry(nil, "sub/module", "refs/heads/sub/module/v1.2.3", ""),
+		Entry(nil, "sub/module/v2", "refs/tags/sub/module/v2.1.0", "v2.1.0"),
+		Entry(nil, "sub/module/v2", "refs/tags/sub/module/v3.0.0", ""),
+		Entry(nil, "sub/module/v2", "refs/tags/sub/module/v2/v2.1.0", ""),
+	)
+
+	It("matches pre-releases only when asked to", func() {
+		Expect(NewGoModuleTagMatcher("sub/module", WithPrereleases())("refs/tags/sub/module/v1.3.0-rc1")).
+			To(Equal("v1.3.0-rc1"))
+	})
+
+	It("finds the latest Go module version tags", func(ctx context.Context) {
+		monorepodir := localremote.CreateTransientModuleRepo()
+		latest := Successful(LatestRelease(ctx, monorepodir, NewGoModuleTagMatcher("sub/module/v2")))
+		Expect(latest.Semver).To(Equal("v2.1.0"))
+		Expect(latest.Ref).To(Equal("refs/tags/sub/module/v2.1.0"))
+		latest = Successful(LatestRelease(ctx, monorepodir, NewGoModuleTagMatcher("other")))
+		Expect(latest.Semver).To(Equal("v3.0.0+incompatible"))
+	})
+
+})
@@ -0,0 +1,119 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/thediveo/gitrepofs/remote"
+)
+
+// peeledSuffix marks the peeled references of annotated tags, as advertised by
+// remote repositories.
+const peeledSuffix = "^{}"
+
+// ReleaseTag describes a version reference in a remote repository.
+type ReleaseTag struct {
+	// Semver is the version as parsed by the version scheme, such as a
+	// VersionMatcherFn. For other schemes, such as [NewCalVerScheme], this is
+	// not a semver but a version in the scheme's format instead.
+	Semver string
+	// Ref is the full reference name, such as "refs/tags/v1.2.3".
+	Ref string
+	// Hash is the object hash the reference points to. For annotated tags,
+	// this is the hash of the tag object, otherwise the commit hash.
+	Hash plumbing.Hash
+	// Commit is the hash of the commit the reference finally points to. For
+	// annotated tags this is the peeled hash, otherwise the same as Hash.
+	Commit plumbing.Hash
+}
+
+// IsAnnotated returns true if the release tag is an annotated tag.
+func (t ReleaseTag) IsAnnotated() bool { return t.Hash != t.Commit }
+
+// ListReleaseTags returns all references in the specified remote git
+// repository that match the specified pattern, especially when combined with
+// [NewPrefixedTagMatcher]. The release tags are sorted in ascending order
+// according to semver precedence, so the latest release tag comes last.
+// Release tags with the same precedence are sorted by their reference names.
+//
+// Please note that ListReleaseTags returns an empty list without error if
+// there are no matching references at all.
+//
+// As ListReleaseTags only lists references without fetching any objects, it
+// cannot verify signatures and thus rejects [remote.WithVerifier]; use
+// [LatestRelease] instead.
+func ListReleaseTags(ctx context.Context, remoteURL string, fn VersionMatcherFn, opts ...remote.Option) ([]ReleaseTag, error) {
+	return ListReleaseTagsForScheme(ctx, remoteURL, fn, opts...)
+}
+
+// ListReleaseTagsForScheme returns all references in the specified remote git
+// repository that match the specified version scheme, sorted in ascending
+// order according to the scheme's version order. Otherwise, it works like
+// [ListReleaseTags].
+func ListReleaseTagsForScheme(ctx context.Context, remoteURL string, scheme Scheme, opts ...remote.Option) ([]ReleaseTag, error) {
+	if err := remote.NewOptions(opts...).RejectVerifier(); err != nil {
+		return nil, err
+	}
+	return listReleaseTags(ctx, remoteURL, scheme, opts)
+}
+
+// listReleaseTags returns all references in the specified remote git
+// repository that match the specified version scheme, sorted in ascending
+// order. In contrast to [ListReleaseTagsForScheme], listReleaseTags leaves
+// any verifier to its callers.
+func listReleaseTags(ctx context.Context, remoteURL string, scheme Scheme, opts []remote.Option) ([]ReleaseTag, error) {
+	refs, err := listReferences(ctx, remoteURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	peeled := map[string]plumbing.Hash{}
+	for _, ref := range refs {
+		if name, ok := strings.CutSuffix(ref.Name().String(), peeledSuffix); ok {
+			peeled[name] = ref.Hash()
+		}
+	}
+	tags := []ReleaseTag{}
+	for _, ref := range refs {
+		name := ref.Name().String()
+		if ref.Type() != plumbing.HashReference || strings.HasSuffix(name, peeledSuffix) {
+			continue
+		}
+		version := scheme.Parse(name)
+		if version == "" {
+			continue
+		}
+		commit, ok := peeled[name]
+		if !ok {
+			commit = ref.Hash()
+		}
+		tags = append(tags, ReleaseTag{
+			Semver: version,
+			Ref:    name,
+			Hash:   ref.Hash(),
+			Commit: commit,
+		})
+	}
+	slices.SortFunc(tags, func(a, b ReleaseTag) int {
+		if c := scheme.Compare(a.Semver, b.Semver); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Ref, b.Ref)
+	})
+	return tags, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package version
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("listing release tags", Ordered, func() {
+
+	var tmprepdir string
+
+	BeforeAll(func() {
+		tmprepdir = localremote.CreateTransientTestRepo()
+	})
+
+	It("lists all matching release tags in order", func(ctx context.Context) {
+		tags := Successful(ListReleaseTags(ctx, tmprepdir, NewPrefixedTagMatcher("", WithPrereleases())))
+		Expect(tags).To(HaveExactElements(
+			HaveField("Ref", "refs/tags/v1.0"),
+			HaveField("Ref", "refs/tags/v1.0.1"),
+			HaveField("Ref", "refs/tags/v1.1.1"),
+			HaveField("Ref", "refs/tags/v2.0.0-rc1"),
+		))
+		Expect(tags[0].Semver).To(Equal("v1.0"))
+		Expect(tags[0].IsAnnotated()).To(BeFalse())
+		Expect(tags[3].Semver).To(Equal("v2.0.0-rc1"))
+
+		repo := Successful(git.PlainOpen(tmprepdir))
+		initial := Successful(repo.ResolveRevision(plumbing.Revision("v1.0")))
+		Expect(tags[0].Commit).To(Equal(*initial))
+		Expect(tags[0].Hash).To(Equal(*initial))
+
+		annotated := Successful(repo.Tag("v1.0.1"))
+		Expect(tags[1].IsAnnotated()).To(BeTrue())
+		Expect(tags[1].Hash).To(Equal(annotated.Hash()))
+		Expect(tags[1].Commit).To(Equal(*initial))
+	})
+
+	It("returns an empty list when nothing matches", func(ctx context.Context) {
+		Expect(ListReleaseTags(ctx, tmprepdir, NewPrefixedTagMatcher("libfoo-"))).To(BeEmpty())
+	})
+
+	It("reports inaccessible remotes", func(ctx context.Context) {
+		Expect(ListReleaseTags(ctx, "/nada", SemverTagMatcher)).Error().To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,226 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/thediveo/gitrepofs/remote"
+	"golang.org/x/mod/modfile"
+)
+
+// RetractFilename is the conventional name of retraction files, see
+// [ParseRetractions].
+const RetractFilename = "RETRACTED"
+
+// Retraction is a single retracted version or a closed interval of retracted
+// versions.
+type Retraction struct {
+	// Low is the lowest retracted version.
+	Low string
+	// High is the highest retracted version; the same as Low for a single
+	// retracted version.
+	High string
+	// Rationale optionally explains why the versions were retracted.
+	Rationale string
+}
+
+// retractingScheme wraps a version scheme, skipping retracted versions.
+type retractingScheme struct {
+	Scheme
+	retractions []Retraction
+}
+
+// ErrInvalidRetraction indicates a retracted version not in the format of the
+// version scheme, or a retraction interval with its low version above its
+// high version.
+var ErrInvalidRetraction = errors.New("invalid retraction")
+
+// Retract returns a [Scheme] that skips the retracted versions, passing all
+// other versions from the specified scheme on. Retracted versions are
+// compared using the specified scheme, so they need to be in the scheme's
+// format, such as "v1.2.3" for [VersionMatcherFn] schemes. Otherwise, Retract
+// returns an error wrapping [ErrInvalidRetraction], as is the case for
+// intervals with their low version above their high version.
+func Retract(scheme Scheme, retractions []Retraction) (Scheme, error) {
+	for _, r := range retractions {
+		for _, version := range []string{r.Low, r.High} {
+			if !scheme.Valid(version) {
+				return nil, fmt.Errorf("retracted version %q not valid in version scheme: %w",
+					version, ErrInvalidRetraction)
+			}
+		}
+		if scheme.Compare(r.Low, r.High) > 0 {
+			return nil, fmt.Errorf("retraction interval [%s, %s] has low version above high version: %w",
+				r.Low, r.High, ErrInvalidRetraction)
+		}
+	}
+	return retractingScheme{Scheme: scheme, retractions: retractions}, nil
+}
+
+// ExcludeVersions returns a [Scheme] that skips the listed versions, passing
+// all other versions from the specified scheme on. See also [Retract].
+func ExcludeVersions(scheme Scheme, versions ...string) (Scheme, error) {
+	retractions := make([]Retraction, 0, len(versions))
+	for _, version := range versions {
+		retractions = append(retractions, Retraction{Low: version, High: version})
+	}
+	return Retract(scheme, retractions)
+}
+
+// Parse returns the version embedded in the refname, or an empty string if
+// the refname doesn't match or the version has been retracted.
+func (s retractingScheme) Parse(refname string) string {
+	version := s.Scheme.Parse(refname)
+	if version == "" {
+		return ""
+	}
+	for _, r := range s.retractions {
+		if s.Compare(version, r.Low) >= 0 && s.Compare(version, r.High) <= 0 {
+			return ""
+		}
+	}
+	return version
+}
+
+// ParseRetractions parses a list of retracted versions, as found in
+// [RetractFilename] files. Each line contains either a single version, such
+// as "v1.2.3", or a closed interval of versions, such as "[v1.0.0, v1.0.5]".
+// Everything following a "#" is a comment and becomes the retraction's
+// rationale. Empty lines are ignored.
+//
+// As ParseRetractions doesn't know the version scheme, it checks only the
+// syntax of the list; [Retract] then checks the retracted versions against
+// the version scheme.
+func ParseRetractions(data []byte) ([]Retraction, error) {
+	retractions := []Retraction{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line, rationale, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		r := Retraction{Rationale: strings.TrimSpace(rationale)}
+		if interval, ok := strings.CutPrefix(line, "["); ok {
+			interval, ok = strings.CutSuffix(interval, "]")
+			low, high, comma := strings.Cut(interval, ",")
+			r.Low, r.High = strings.TrimSpace(low), strings.TrimSpace(high)
+			if !ok || !comma || r.Low == "" || r.High == "" ||
+				strings.ContainsAny(r.Low, " \t") || strings.ContainsAny(r.High, " \t") {
+				return nil, fmt.Errorf("invalid retraction interval %q in line %d", line, lineno)
+			}
+		} else {
+			if strings.ContainsAny(line, " \t,[]") {
+				return nil, fmt.Errorf("invalid retracted version %q in line %d", line, lineno)
+			}
+			r.Low, r.High = line, line
+		}
+		retractions = append(retractions, r)
+	}
+	return retractions, scanner.Err()
+}
+
+// ParseGoModRetractions returns the versions retracted by the “retract”
+// directives in the specified go.mod contents.
+func ParseGoModRetractions(gomod []byte) ([]Retraction, error) {
+	f, err := modfile.ParseLax("go.mod", gomod, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go.mod, reason: %w", err)
+	}
+	retractions := make([]Retraction, 0, len(f.Retract))
+	for _, r := range f.Retract {
+		retractions = append(retractions, Retraction{
+			Low:       r.Low,
+			High:      r.High,
+			Rationale: r.Rationale,
+		})
+	}
+	return retractions, nil
+}
+
+// RetractedVersions returns the versions retracted by the named file on the
+// default branch of the specified remote repository. If the file is named
+// “go.mod” then the retractions are read from its “retract” directives, see
+// [ParseGoModRetractions], otherwise from a list of versions, see
+// [ParseRetractions]. If the file doesn't exist, RetractedVersions returns an
+// empty list without error. As RetractedVersions doesn't verify the
+// signature of the default branch's head commit, it rejects
+// [remote.WithVerifier].
+//
+// Pass the retractions to [Retract] in order to skip the retracted versions
+// when determining the latest release:
+//
+//	retractions, err := RetractedVersions(ctx, remoteURL, RetractFilename)
+//	scheme, err := Retract(SemverTagMatcher, retractions)
+//	semver, ref, err := LatestReleaseTagForScheme(ctx, remoteURL, scheme)
+func RetractedVersions(ctx context.Context, remoteURL string, name string, opts ...remote.Option) ([]Retraction, error) {
+	o := remote.NewOptions(opts...)
+	if o.Err != nil {
+		return nil, o.Err
+	}
+	if err := o.RejectPins(); err != nil {
+		return nil, err
+	}
+	if err := o.RejectVerifier(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.Context(ctx)
+	defer cancel()
+	cloneOpts := o.CloneOptions(remoteURL)
+	cloneOpts.SingleBranch = true
+	cloneOpts.Depth = 1
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, cloneOpts)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot clone remote repository %q, reason: %w", remoteURL, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"no default branch in remote repository %q, reason: %w", remoteURL, err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"invalid default branch commit in remote repository %q, reason: %w", remoteURL, err)
+	}
+	file, err := commit.File(name)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return []Retraction{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %q, reason: %w", name, err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %q, reason: %w", name, err)
+	}
+	if path.Base(name) == "go.mod" {
+		return ParseGoModRetractions([]byte(contents))
+	}
+	return ParseRetractions([]byte(contents))
+}
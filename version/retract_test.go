@@ -0,0 +1,139 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package version
+
+import (
+	"context"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("retracted versions", func() {
+
+	It("parses retraction lists", func() {
+		Expect(ParseRetractions([]byte(`# retracted releases
+
+v1.2.3 # broken build
+  [v1.0.0, v1.0.5]
+[v2.0.0,v2.0.1]#
+`))).To(HaveExactElements(
+			Retraction{Low: "v1.2.3", High: "v1.2.3", Rationale: "broken build"},
+			Retraction{Low: "v1.0.0", High: "v1.0.5"},
+			Retraction{Low: "v2.0.0", High: "v2.0.1"},
+		))
+		Expect(ParseRetractions(nil)).To(BeEmpty())
+	})
+
+	DescribeTable("rejects invalid retraction lists",
+		func(data string, experr string) {
+			Expect(ParseRetractions([]byte(data))).Error().To(MatchError(ContainSubstring(experr)))
+		},
+		Entry(nil, "v1.0.0\nv1.0.1 v1.0.2", `invalid retracted version "v1.0.1 v1.0.2" in line 2`),
+		Entry(nil, "v1.0.0]", "invalid retracted version"),
+		Entry(nil, "[v1.0.0, v1.0.5", "invalid retraction interval"),
+		Entry(nil, "[v1.0.0 v1.0.5]", "invalid retraction interval"),
+		Entry(nil, "[, v1.0.5]", "invalid retraction interval"),
+		Entry(nil, "[v1.0.0, v1.0.5 v1.0.6]", "invalid retraction interval"),
+	)
+
+	It("parses go.mod retractions", func() {
+		Expect(ParseGoModRetractions([]byte(`module example.org/foo
+
+retract (
+	v1.0.0 // published accidentally
+	[v1.1.0, v1.1.5]
+)
+`))).To(HaveExactElements(
+			Retraction{Low: "v1.0.0", High: "v1.0.0", Rationale: "published accidentally"},
+			Retraction{Low: "v1.1.0", High: "v1.1.5"},
+		))
+		Expect(ParseGoModRetractions([]byte("retract ("))).Error().To(HaveOccurred())
+	})
+
+	It("skips retracted and excluded versions", func() {
+		rs := Successful(Retract(SemverTagMatcher, []Retraction{
+			{Low: "v1.2.3", High: "v1.2.3"},
+			{Low: "v2.0.0", High: "v2.1"},
+		}))
+		Expect(rs.Parse("refs/tags/v1.2.2")).To(Equal("v1.2.2"))
+		Expect(rs.Parse("refs/tags/v1.2.3")).To(BeEmpty())
+		Expect(rs.Parse("refs/tags/v2.0.5")).To(BeEmpty())
+		Expect(rs.Parse("refs/tags/v2.1.0")).To(BeEmpty())
+		Expect(rs.Parse("refs/tags/v2.1.1")).To(Equal("v2.1.1"))
+		Expect(rs.Parse("refs/tags/foobar")).To(BeEmpty())
+		Expect(rs.Compare("v1.0.0", "v1.1.0")).To(Equal(-1))
+
+		es := Successful(ExcludeVersions(NewSequenceScheme("build-"), "42", "0666"))
+		Expect(es.Parse("refs/tags/build-41")).To(Equal("41"))
+		Expect(es.Parse("refs/tags/build-42")).To(BeEmpty())
+		Expect(es.Parse("refs/tags/build-666")).To(BeEmpty())
+	})
+
+	DescribeTable("rejects retractions invalid in the version scheme",
+		func(scheme Scheme, retractions []Retraction) {
+			Expect(Retract(scheme, retractions)).Error().To(MatchError(ErrInvalidRetraction))
+		},
+		Entry("missing v", SemverTagMatcher, []Retraction{{Low: "1.2.3", High: "1.2.3"}}),
+		Entry("invalid low", SemverTagMatcher, []Retraction{{Low: "1.0.0", High: "v9.9.9"}}),
+		Entry("invalid high", SemverTagMatcher, []Retraction{{Low: "v1.0.0", High: "latest"}}),
+		Entry("low above high", SemverTagMatcher, []Retraction{{Low: "v1.0.5", High: "v1.0.0"}}),
+		Entry("calver", NewCalVerScheme(""), []Retraction{{Low: "2024.13", High: "2024.13"}}),
+		Entry("sequence low above high", NewSequenceScheme(""), []Retraction{{Low: "10", High: "9"}}),
+	)
+
+	It("rejects excluded versions invalid in the version scheme", func() {
+		Expect(ExcludeVersions(SemverTagMatcher, "v1.0.0", "1.2.3")).Error().To(MatchError(ErrInvalidRetraction))
+		Expect(ExcludeVersions(NewDateStampScheme("", "20060102"), "2024-03-01")).Error().To(MatchError(ErrInvalidRetraction))
+	})
+
+	Context("with a remote repository", Ordered, func() {
+
+		var monorepodir string
+
+		BeforeAll(func() {
+			monorepodir = localremote.CreateTransientModuleRepo()
+		})
+
+		It("skips versions retracted on the default branch", func(ctx context.Context) {
+			matcher := NewPrefixedTagMatcher("sub/module/")
+			latest := Successful(LatestRelease(ctx, monorepodir, matcher))
+			Expect(latest.Semver).To(Equal("v3.0.0"))
+
+			retractions := Successful(RetractedVersions(ctx, monorepodir, RetractFilename))
+			Expect(retractions).To(HaveLen(2))
+			semver, ref := Successful2R(LatestReleaseTagForScheme(ctx, monorepodir,
+				Successful(Retract(matcher, retractions))))
+			Expect(semver).To(Equal("v1.1.0"))
+			Expect(ref).To(Equal("refs/tags/sub/module/v1.1.0"))
+
+			retractions = Successful(RetractedVersions(ctx, monorepodir, "sub/module/go.mod"))
+			Expect(retractions).To(ConsistOf(HaveField("Rationale", "oops")))
+			latest = Successful(LatestReleaseForScheme(ctx, monorepodir,
+				Successful(Retract(matcher, retractions))))
+			Expect(latest.Semver).To(Equal("v2.1.0"))
+		})
+
+		It("doesn't retract anything without retractions", func(ctx context.Context) {
+			Expect(RetractedVersions(ctx, monorepodir, "nada/"+RetractFilename)).To(BeEmpty())
+			Expect(RetractedVersions(ctx, "/nada", RetractFilename)).Error().To(HaveOccurred())
+		})
+
+	})
+
+})
@@ -0,0 +1,233 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// Scheme is a version scheme that parses versions from reference names and
+// orders them. [VersionMatcherFn] implements the semver scheme, while
+// [NewCalVerScheme], [NewDateStampScheme], and [NewSequenceScheme] return
+// schemes for calendar versions, date stamps, and numeric sequences.
+type Scheme interface {
+	// Parse returns the version embedded in a refname. If the refname should
+	// not be taken into account, then Parse returns an empty string instead.
+	Parse(refname string) (version string)
+	// Compare returns -1, 0, or +1 depending on whether version v is less
+	// than, equal to, or greater than version w, where both versions have been
+	// returned by Parse.
+	Compare(v, w string) int
+	// Valid returns true if the version is in the scheme's format, such as
+	// the versions returned by Parse.
+	Valid(version string) bool
+}
+
+var _ Scheme = VersionMatcherFn(nil)
+
+// Parse returns the semver returned by the VersionMatcherFn, or an empty
+// string if the VersionMatcherFn returned an invalid semver.
+func (fn VersionMatcherFn) Parse(refname string) string {
+	version := fn(refname)
+	if !fn.Valid(version) {
+		return ""
+	}
+	return version
+}
+
+// Valid returns true if the version is a valid semver, as implemented by
+// [semver.IsValid].
+func (fn VersionMatcherFn) Valid(version string) bool {
+	return semver.IsValid(version)
+}
+
+// Compare compares two semvers according to semver precedence, as
+// implemented by [semver.Compare].
+func (fn VersionMatcherFn) Compare(v, w string) int {
+	return semver.Compare(v, w)
+}
+
+// tagVersion returns the part of the refname following "refs/tags/" and the
+// specified literal prefix, or an empty string if the refname isn't a tag
+// with the prefix.
+func tagVersion(refname string, prefix string) string {
+	version, ok := strings.CutPrefix(refname, "refs/tags/"+prefix)
+	if !ok {
+		return ""
+	}
+	return version
+}
+
+// compareNumbers compares two non-negative decimal numbers of arbitrary
+// length, ignoring any leading zeros.
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumber returns true if s is a non-empty string of decimal digits.
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// calVerScheme implements calendar versioning.
+type calVerScheme struct {
+	prefix string
+}
+
+// NewCalVerScheme returns a [Scheme] for calendar versioning, matching only
+// tags in the format <prefix><calver>, where the prefix is literal. Calendar
+// versions consist of a year (“YYYY” or “YY”), a month (“MM” or “0M”) and
+// optionally up to two further numeric elements, such as a day or a micro
+// version, all separated by dots: for instance, “2024.03.1”, “24.10”, and
+// “2024.3.15.2”. Calendar versions are ordered numerically element by element,
+// so “24.9” comes before “24.10”, and “2024.03” equals “2024.3.0”.
+func NewCalVerScheme(prefix string) Scheme {
+	return calVerScheme{prefix: prefix}
+}
+
+// Parse returns the calendar version in the refname, or an empty string.
+func (s calVerScheme) Parse(refname string) string {
+	version := tagVersion(refname, s.prefix)
+	if !s.Valid(version) {
+		return ""
+	}
+	return version
+}
+
+// Valid returns true if the version is a calendar version.
+func (s calVerScheme) Valid(version string) bool {
+	elements := strings.Split(version, ".")
+	if len(elements) < 2 || len(elements) > 4 {
+		return false
+	}
+	for _, element := range elements {
+		if !isNumber(element) {
+			return false
+		}
+	}
+	if year := elements[0]; len(year) != 2 && len(year) != 4 {
+		return false
+	}
+	month, _ := strconv.Atoi(elements[1])
+	return month >= 1 && month <= 12 && len(elements[1]) <= 2
+}
+
+// Compare compares two calendar versions numerically element by element,
+// with missing elements counting as zero.
+func (s calVerScheme) Compare(v, w string) int {
+	ve, we := strings.Split(v, "."), strings.Split(w, ".")
+	for idx := range max(len(ve), len(we)) {
+		a, b := "0", "0"
+		if idx < len(ve) {
+			a = ve[idx]
+		}
+		if idx < len(we) {
+			b = we[idx]
+		}
+		if c := compareNumbers(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// dateStampScheme implements date-stamped versions.
+type dateStampScheme struct {
+	prefix string
+	layout string
+}
+
+// NewDateStampScheme returns a [Scheme] for date-stamped versions, matching
+// only tags in the format <prefix><datestamp>, where the prefix is literal and
+// the date stamp is in the specified layout, as understood by [time.Parse].
+// For instance, use the layout “20060102” for tags such as
+// “release-20240301”. Date stamps are ordered chronologically.
+func NewDateStampScheme(prefix string, layout string) Scheme {
+	return dateStampScheme{prefix: prefix, layout: layout}
+}
+
+// Parse returns the date stamp in the refname, or an empty string.
+func (s dateStampScheme) Parse(refname string) string {
+	version := tagVersion(refname, s.prefix)
+	if !s.Valid(version) {
+		return ""
+	}
+	return version
+}
+
+// Valid returns true if the version is a date stamp in the scheme's layout.
+func (s dateStampScheme) Valid(version string) bool {
+	if version == "" {
+		return false
+	}
+	_, err := time.Parse(s.layout, version)
+	return err == nil
+}
+
+// Compare compares two date stamps chronologically.
+func (s dateStampScheme) Compare(v, w string) int {
+	vt, _ := time.Parse(s.layout, v)
+	wt, _ := time.Parse(s.layout, w)
+	return vt.Compare(wt)
+}
+
+// sequenceScheme implements numeric sequence versions.
+type sequenceScheme struct {
+	prefix string
+}
+
+// NewSequenceScheme returns a [Scheme] for numerically sequenced versions,
+// matching only tags in the format <prefix><number>, where the prefix is
+// literal, such as “build-42” or “r1337”. Numbers are ordered numerically, so
+// “build-9” comes before “build-10”.
+func NewSequenceScheme(prefix string) Scheme {
+	return sequenceScheme{prefix: prefix}
+}
+
+// Parse returns the sequence number in the refname, or an empty string.
+func (s sequenceScheme) Parse(refname string) string {
+	version := tagVersion(refname, s.prefix)
+	if !s.Valid(version) {
+		return ""
+	}
+	return version
+}
+
+// Valid returns true if the version is a sequence number.
+func (s sequenceScheme) Valid(version string) bool {
+	return isNumber(version)
+}
+
+// Compare compares two sequence numbers numerically.
+func (s sequenceScheme) Compare(v, w string) int {
+	return compareNumbers(v, w)
+}
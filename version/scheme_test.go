@@ -0,0 +1,116 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package version
+
+import (
+	"context"
+
+	"github.com/thediveo/gitrepofs/test/localremote"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("version schemes", func() {
+
+	DescribeTable("parses versions",
+		func(scheme Scheme, refname string, expected string) {
+			Expect(scheme.Parse(refname)).To(Equal(expected))
+		},
+		Entry(nil, SemverTagMatcher, "refs/tags/v1.2.3", "v1.2.3"),
+		Entry(nil, VersionMatcherFn(func(string) string { return "1.2.3" }), "refs/tags/v1.2.3", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.03.1", "2024.03.1"),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/24.10", "24.10"),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.3.15.2", "2024.3.15.2"),
+		Entry(nil, NewCalVerScheme("v"), "refs/tags/v2024.3", "2024.3"),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.13.1", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.0.1", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.003.1", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/202.03.1", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.03.1.2.3", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/tags/2024.03.x", ""),
+		Entry(nil, NewCalVerScheme(""), "refs/heads/2024.03.1", ""),
+		Entry(nil, NewDateStampScheme("release-", "20060102"), "refs/tags/release-20240301", "20240301"),
+		Entry(nil, NewDateStampScheme("release-", "20060102"), "refs/tags/release-20241301", ""),
+		Entry(nil, NewDateStampScheme("release-", "20060102"), "refs/tags/release-", ""),
+		Entry(nil, NewDateStampScheme("", "2006-01-02"), "refs/tags/2024-03-01", "2024-03-01"),
+		Entry(nil, NewSequenceScheme("build-"), "refs/tags/build-42", "42"),
+		Entry(nil, NewSequenceScheme("build-"), "refs/tags/build-", ""),
+		Entry(nil, NewSequenceScheme("build-"), "refs/tags/build-4a", ""),
+		Entry(nil, NewSequenceScheme("r"), "refs/tags/r1337", "1337"),
+	)
+
+	DescribeTable("validates versions",
+		func(scheme Scheme, version string, valid bool) {
+			Expect(scheme.Valid(version)).To(Equal(valid))
+		},
+		Entry(nil, SemverTagMatcher, "v1.2.3", true),
+		Entry(nil, SemverTagMatcher, "1.2.3", false),
+		Entry(nil, NewCalVerScheme("v"), "2024.03.1", true),
+		Entry(nil, NewCalVerScheme("v"), "v2024.03.1", false),
+		Entry(nil, NewDateStampScheme("release-", "20060102"), "20240301", true),
+		Entry(nil, NewDateStampScheme("release-", "20060102"), "", false),
+		Entry(nil, NewSequenceScheme("build-"), "42", true),
+		Entry(nil, NewSequenceScheme("build-"), "build-42", false),
+	)
+
+	DescribeTable("compares versions",
+		func(scheme Scheme, v, w string, expected int) {
+			Expect(scheme.Compare(v, w)).To(Equal(expected))
+			Expect(scheme.Compare(w, v)).To(Equal(-expected))
+		},
+		Entry(nil, SemverTagMatcher, "v1.2.3", "v1.10.0", -1),
+		Entry(nil, NewCalVerScheme(""), "24.9", "24.10", -1),
+		Entry(nil, NewCalVerScheme(""), "2024.03", "2024.3.0", 0),
+		Entry(nil, NewCalVerScheme(""), "2024.03.1", "2024.3", 1),
+		Entry(nil, NewCalVerScheme(""), "2024.12.31", "2025.01.1", -1),
+		Entry(nil, NewDateStampScheme("", "20060102"), "20231224", "20240301", -1),
+		Entry(nil, NewDateStampScheme("", "20060102"), "20240301", "20240301", 0),
+		Entry(nil, NewSequenceScheme(""), "9", "10", -1),
+		Entry(nil, NewSequenceScheme(""), "007", "7", 0),
+		Entry(nil, NewSequenceScheme(""), "0", "00", 0),
+	)
+
+	Context("with a remote repository", Ordered, func() {
+
+		var tmprepdir string
+
+		BeforeAll(func() {
+			tmprepdir = localremote.CreateTransientTestRepo()
+		})
+
+		DescribeTable("finds the latest version",
+			func(ctx context.Context, scheme Scheme, expected string, expref string) {
+				version, ref := Successful2R(LatestReleaseTagForScheme(ctx, tmprepdir, scheme))
+				Expect(version).To(Equal(expected))
+				Expect(ref).To(Equal(expref))
+			},
+			Entry(nil, NewCalVerScheme("cal-"), "24.10.0", "refs/tags/cal-24.10.0"),
+			Entry(nil, NewDateStampScheme("release-", "20060102"), "20240301", "refs/tags/release-20240301"),
+			Entry(nil, NewSequenceScheme("build-"), "10", "refs/tags/build-10"),
+		)
+
+		It("lists versions in scheme order", func(ctx context.Context) {
+			Expect(ListReleaseTagsForScheme(ctx, tmprepdir, NewCalVerScheme("cal-"))).To(HaveExactElements(
+				HaveField("Semver", "24.9.1"),
+				HaveField("Semver", "24.10.0"),
+			))
+		})
+
+	})
+
+})
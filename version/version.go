@@ -24,6 +24,7 @@ import (
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/thediveo/gitrepofs/remote"
 	"golang.org/x/mod/semver"
 )
 
@@ -34,62 +35,209 @@ type VersionMatcherFn func(refname string) (semver string)
 
 // SemverTagMatcher is a version tag matcher that matches only on tags in semver
 // version. That is, with an optional "v" prefix in the "MAJOR.MINOR.PATCH"
-// format, where MINOR and PATCH are optional.
+// format, where MINOR and PATCH are optional. Pre-release versions are
+// ignored.
 var SemverTagMatcher = NewPrefixedTagMatcher("")
 
+// MatcherOption configures the matchers returned by [NewPrefixedTagMatcher],
+// [NewBranchMatcher], and [NewGoModuleTagMatcher].
+type MatcherOption func(*matcherOptions)
+
+type matcherOptions struct {
+	prereleases bool
+}
+
+// WithPrereleases makes a tag matcher also match pre-release versions, such as
+// "v2.0.0-rc1". [LatestReleaseTag] then orders pre-releases according to semver
+// precedence, so "v2.0.0-rc1" comes before "v2.0.0", but after "v1.9.9".
+func WithPrereleases() MatcherOption {
+	return func(o *matcherOptions) {
+		o.prereleases = true
+	}
+}
+
+// semverRegex matches semver 2.0 versions with an optional "v" prefix, where
+// the MINOR and PATCH elements are optional. The first group contains the
+// version, the second group any pre-release information.
+const semverRegex = `(v?\d+(?:\.\d+(?:\.\d+)?)?` +
+	`(-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?` +
+	`(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?)`
+
 // NewPrefixedTagMatcher returns a VersionMatcherFn to be used with
 // [LatestReleaseTag]. The returned function only matches tags (/ref/tags/...)
 // in the format <prefix><semver>. In particular, semvers must be in the format
-// MAJOR, MAJOR.MINOR and MAJOR.MINOR.PATH with an optional "v" prefix, but no
-// BUILD and PRERELEASE elements.
-func NewPrefixedTagMatcher(prefix string) VersionMatcherFn {
-	re := regexp.MustCompile(`(?m)^refs/tags/` + prefix + `((?:v)?\d(?:\.\d+(?:\.\d+)?)?)$`)
+// MAJOR, MAJOR.MINOR and MAJOR.MINOR.PATH with an optional "v" prefix. Full
+// semver 2.0 versions can have additional PRERELEASE and BUILD elements, such
+// as in "v1.2.3-rc1+build5"; shortened versions cannot.
+//
+// Pre-release versions are ignored, unless the [WithPrereleases] option has
+// been specified. BUILD metadata is kept, albeit not taken into account when
+// comparing versions.
+func NewPrefixedTagMatcher(prefix string, opts ...MatcherOption) VersionMatcherFn {
+	return newRefMatcher(`^refs/tags/`+prefix, ``, opts)
+}
+
+// NewBranchMatcher returns a VersionMatcherFn to be used with
+// [LatestReleaseTag] in order to follow release branches instead of tags. The
+// returned function only matches branches (/refs/heads/...) in the format
+// <prefix><semver><suffix>, where prefix and suffix are literal. For instance,
+// use the prefix "release-" for branches such as "release-1.4", or the prefix
+// "stable/" and the suffix ".x" for branches such as "stable/2.x". Semvers are
+// in the same formats as for [NewPrefixedTagMatcher].
+//
+// [LatestRelease] then returns the latest release branch together with the
+// commit hash of its head.
+func NewBranchMatcher(prefix string, suffix string, opts ...MatcherOption) VersionMatcherFn {
+	return newRefMatcher(`^refs/heads/`+regexp.QuoteMeta(prefix), regexp.QuoteMeta(suffix), opts)
+}
+
+// newRefMatcher returns a VersionMatcherFn matching reference names consisting
+// of the specified prefix and suffix regular expressions with a semver in
+// between.
+func newRefMatcher(prefix string, suffix string, opts []MatcherOption) VersionMatcherFn {
+	o := matcherOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	re := regexp.MustCompile(`(?m)` + prefix + semverRegex + suffix + `$`)
 	return func(refname string) string {
 		match := re.FindStringSubmatch(refname)
 		if match == nil {
 			return ""
 		}
-		semver := match[1] // 1st. group contains the semver string
-		if !strings.HasPrefix(semver, "v") {
-			semver = "v" + semver
-		}
-		return semver
+		return normalize(match[1], match[2], o)
+	}
+}
+
+// normalize the specified (full) semver string, returning "" if it isn't a
+// valid semver or a pre-release version not to be included.
+func normalize(version string, prerelease string, o matcherOptions) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return ""
+	}
+	if prerelease != "" && !o.prereleases {
+		return ""
 	}
+	return version
 }
 
 // LatestReleaseTag determines the latest release tag in the specified remote
 // git repository that matches the specified pattern, especially when combined
-// with [NewPrefixedTagMatcher].
-func LatestReleaseTag(ctx context.Context, remoteURL string, fn VersionMatcherFn) (semanticver string, ref string, err error) {
-	remote := git.NewRemote(
+// with [NewPrefixedTagMatcher]. Versions are ordered according to semver
+// precedence, as implemented by [semver.Compare]. In order to follow release
+// branches instead of tags, use [NewBranchMatcher]. For other version schemes,
+// such as calendar versions, use [LatestReleaseTagForScheme].
+//
+// Use the options from the [remote] package to configure authentication,
+// proxies, CA bundles, and timeouts. With [remote.WithVerifier], the latest
+// release's annotated tag, or otherwise its commit, must carry a valid
+// signature.
+//
+// Use [LatestRelease] to additionally get the tag object and commit hashes.
+func LatestReleaseTag(ctx context.Context, remoteURL string, fn VersionMatcherFn, opts ...remote.Option) (semanticver string, ref string, err error) {
+	return LatestReleaseTagForScheme(ctx, remoteURL, fn, opts...)
+}
+
+// LatestReleaseTagForScheme determines the latest release tag in the
+// specified remote git repository that matches the specified version scheme,
+// with versions ordered according to the scheme. For calendar versions, date
+// stamps, and numeric sequences, see [NewCalVerScheme], [NewDateStampScheme],
+// and [NewSequenceScheme]. Otherwise, it works like [LatestReleaseTag].
+func LatestReleaseTagForScheme(ctx context.Context, remoteURL string, scheme Scheme, opts ...remote.Option) (version string, ref string, err error) {
+	latest, err := LatestReleaseForScheme(ctx, remoteURL, scheme, opts...)
+	if err != nil {
+		return "", "", err
+	}
+	return latest.Semver, latest.Ref, nil
+}
+
+// LatestRelease determines the latest release tag in the specified remote git
+// repository that matches the specified pattern, returning its semver,
+// reference name, as well as the tag object hash and the (peeled) commit hash.
+//
+// For fully pinned fetches, pass the commit hash (as a string) as the revision
+// to [github.com/thediveo/gitrepofs.NewForRevision].
+func LatestRelease(ctx context.Context, remoteURL string, fn VersionMatcherFn, opts ...remote.Option) (ReleaseTag, error) {
+	return LatestReleaseForScheme(ctx, remoteURL, fn, opts...)
+}
+
+// LatestReleaseForScheme determines the latest release tag in the specified
+// remote git repository that matches the specified version scheme, returning
+// its version, reference name, as well as the tag object hash and the
+// (peeled) commit hash. Otherwise, it works like [LatestRelease].
+func LatestReleaseForScheme(ctx context.Context, remoteURL string, scheme Scheme, opts ...remote.Option) (ReleaseTag, error) {
+	tags, err := listReleaseTags(ctx, remoteURL, scheme, opts)
+	if err != nil {
+		return ReleaseTag{}, err
+	}
+	if len(tags) == 0 {
+		return ReleaseTag{}, fmt.Errorf(
+			"no matching version reference in remote %q at all", remoteURL)
+	}
+	latest := tags[len(tags)-1]
+	if err := verifyRelease(ctx, remoteURL, latest, opts); err != nil {
+		return ReleaseTag{}, err
+	}
+	return latest, nil
+}
+
+// verifyRelease verifies the signature of the release's annotated tag, or
+// otherwise its commit, if a verifier has been configured. As only the
+// references have been listed so far, verifyRelease needs to fetch the
+// release's objects first.
+func verifyRelease(ctx context.Context, remoteURL string, release ReleaseTag, opts []remote.Option) error {
+	o := remote.NewOptions(opts...)
+	if o.Err != nil {
+		return o.Err
+	}
+	if o.Verifier == nil {
+		return nil
+	}
+	ctx, cancel := o.Context(ctx)
+	defer cancel()
+	cloneOpts := o.CloneOptions(remoteURL)
+	cloneOpts.ReferenceName = plumbing.ReferenceName(release.Ref)
+	cloneOpts.SingleBranch = true
+	cloneOpts.Depth = 1
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, cloneOpts)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot clone remote repository %q, reason: %w", remoteURL, err)
+	}
+	if err := o.Verify(repo, release.Hash); err != nil {
+		return fmt.Errorf(
+			"cannot verify release %q in remote repository %q, reason: %w",
+			release.Ref, remoteURL, err)
+	}
+	return nil
+}
+
+// listReferences returns the references in the specified remote repository,
+// including the peeled references for annotated tags with their names ending
+// in "^{}".
+func listReferences(ctx context.Context, remoteURL string, opts []remote.Option) ([]*plumbing.Reference, error) {
+	o := remote.NewOptions(opts...)
+	if o.Err != nil {
+		return nil, o.Err
+	}
+	if err := o.RejectPins(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := o.Context(ctx)
+	defer cancel()
+	r := git.NewRemote(
 		memory.NewStorage(),
 		&config.RemoteConfig{
 			URLs: []string{remoteURL},
 		})
-	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	refs, err := r.ListContext(ctx, o.ListOptions())
 	if err != nil {
-		return "", "", fmt.Errorf(
+		return nil, fmt.Errorf(
 			"cannot list references in remote %q repository, reason: %w",
 			remoteURL, err)
 	}
-	// an invalid semver which is automatically considered to be before any
-	// valid semver.
-	latest := ""
-	latestref := ""
-	for _, ref := range refs {
-		if ref.Type() != plumbing.HashReference {
-			continue
-		}
-		version := fn(ref.Name().String())
-		if semver.Compare(version, latest) <= 0 {
-			continue
-		}
-		latest = version
-		latestref = ref.Name().String()
-	}
-	if latestref == "" {
-		return "", "", fmt.Errorf(
-			"no matching version reference in remote %q at all", remoteURL)
-	}
-	return latest, latestref, nil
+	return refs, nil
 }
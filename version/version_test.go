@@ -16,7 +16,11 @@ package version
 
 import (
 	"context"
+	"time"
 
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/thediveo/gitrepofs/remote"
+	"github.com/thediveo/gitrepofs/signature"
 	"github.com/thediveo/gitrepofs/test/localremote"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -39,9 +43,49 @@ var _ = Describe("finding the latest version in a remote repository", func() {
 			Expect(ptm("refs/tags/libfoo-1.2")).To(Equal("v1.2"))
 			Expect(ptm("refs/tags/libfoo-v1.2")).To(Equal("v1.2"))
 			Expect(ptm("refs/tags/libfoo-v1.2.3")).To(Equal("v1.2.3"))
+			Expect(ptm("refs/tags/libfoo-v12.34.56")).To(Equal("v12.34.56"))
+		})
+
+		It("matches full semver versions", func() {
+			ptm := NewPrefixedTagMatcher("libfoo-")
+			Expect(ptm("refs/tags/libfoo-1.2.3+build5")).To(Equal("v1.2.3+build5"))
+			Expect(ptm("refs/tags/libfoo-v2.0.0-rc1")).To(BeEmpty())
+			Expect(ptm("refs/tags/libfoo-v2.0-rc1")).To(BeEmpty())
+
+			ptm = NewPrefixedTagMatcher("libfoo-", WithPrereleases())
+			Expect(ptm("refs/tags/libfoo-v2.0.0-rc1")).To(Equal("v2.0.0-rc1"))
+			Expect(ptm("refs/tags/libfoo-2.0.0-rc.1+build.5")).To(Equal("v2.0.0-rc.1+build.5"))
+			Expect(ptm("refs/tags/libfoo-v2.0.0-rc1..2")).To(BeEmpty())
+			Expect(ptm("refs/tags/libfoo-v2.0.0-01")).To(BeEmpty())
+			Expect(ptm("refs/tags/libfoo-v1.2")).To(Equal("v1.2"))
 		})
 	})
 
+	Context("branch matcher", func() {
+
+		It("matches release branches", func() {
+			bm := NewBranchMatcher("release-", "")
+			Expect(bm("refs/heads/release-1.4")).To(Equal("v1.4"))
+			Expect(bm("refs/heads/release-v1.4.2")).To(Equal("v1.4.2"))
+			Expect(bm("refs/tags/release-1.4")).To(BeEmpty())
+			Expect(bm("refs/heads/release-1.4-rc1")).To(BeEmpty())
+			Expect(bm("refs/heads/prerelease-1.4")).To(BeEmpty())
+
+			bm = NewBranchMatcher("stable/", ".x")
+			Expect(bm("refs/heads/stable/2.x")).To(Equal("v2"))
+			Expect(bm("refs/heads/stable/2.1.x")).To(Equal("v2.1"))
+			Expect(bm("refs/heads/stable/2")).To(BeEmpty())
+			Expect(bm("refs/heads/stable/2.xx")).To(BeEmpty())
+			Expect(bm("refs/heads/stable/2ax")).To(BeEmpty())
+		})
+
+		It("matches pre-release branches only when asked to", func() {
+			Expect(NewBranchMatcher("release-", "", WithPrereleases())("refs/heads/release-1.4.0-rc1")).
+				To(Equal("v1.4.0-rc1"))
+		})
+
+	})
+
 	Context("with a remote repository", Ordered, func() {
 
 		var tmprepdir string
@@ -63,6 +107,101 @@ var _ = Describe("finding the latest version in a remote repository", func() {
 			Expect(ref).To(Equal("refs/tags/v1.1.1"))
 		})
 
+		It("reports tag object and commit hashes of the latest version", func(ctx context.Context) {
+			latest, err := LatestRelease(ctx, tmprepdir,
+				NewConstrainedMatcher(SemverTagMatcher, MustParseConstraint("1.0")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest.Semver).To(Equal("v1.0.1"))
+			Expect(latest.Ref).To(Equal("refs/tags/v1.0.1"))
+			Expect(latest.IsAnnotated()).To(BeTrue())
+
+			v10, err := LatestRelease(ctx, tmprepdir,
+				NewConstrainedMatcher(SemverTagMatcher, MustParseConstraint("<1.0.1")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v10.Semver).To(Equal("v1.0"))
+			Expect(latest.Commit).To(Equal(v10.Commit))
+			Expect(latest.Hash).NotTo(Equal(v10.Hash))
+		})
+
+		It("finds latest and greatest pre-release version", func(ctx context.Context) {
+			semver, ref, err := LatestReleaseTag(ctx, tmprepdir,
+				NewPrefixedTagMatcher("", WithPrereleases()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(semver).To(Equal("v2.0.0-rc1"))
+			Expect(ref).To(Equal("refs/tags/v2.0.0-rc1"))
+		})
+
+		It("passes remote options on", func(ctx context.Context) {
+			Expect(LatestReleaseTag(ctx, tmprepdir, SemverTagMatcher, remote.WithTimeout(time.Nanosecond))).
+				Error().To(MatchError(context.DeadlineExceeded))
+			Expect(LatestReleaseTag(ctx, tmprepdir, SemverTagMatcher, remote.WithTimeout(time.Minute))).
+				Error().NotTo(HaveOccurred())
+		})
+
+		It("rejects pinning options", func(ctx context.Context) {
+			pin := func(o *remote.Options) {
+				o.ExpectedCommit = plumbing.NewHash("0123456789012345678901234567890123456789")
+			}
+			Expect(LatestReleaseTag(ctx, tmprepdir, SemverTagMatcher, pin)).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+			Expect(ListReleaseTags(ctx, tmprepdir, SemverTagMatcher, pin)).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+			Expect(RetractedVersions(ctx, tmprepdir, RetractFilename, pin)).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+		})
+
+		It("finds the latest release branch head", func(ctx context.Context) {
+			semver, ref, err := LatestReleaseTag(ctx, tmprepdir, NewBranchMatcher("release-", ""))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(semver).To(Equal("v1.1"))
+			Expect(ref).To(Equal("refs/heads/release-1.1"))
+
+			latest, err := LatestRelease(ctx, tmprepdir, NewBranchMatcher("stable/", ".x"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest.Ref).To(Equal("refs/heads/stable/2.x"))
+			Expect(latest.IsAnnotated()).To(BeFalse())
+			v2rc1, err := LatestRelease(ctx, tmprepdir, NewPrefixedTagMatcher("", WithPrereleases()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest.Commit).To(Equal(v2rc1.Commit))
+		})
+
+	})
+
+	Context("verifying signatures", Ordered, func() {
+
+		var signed localremote.SignedRepo
+		var verifier *signature.Verifier
+
+		BeforeAll(func() {
+			signed = localremote.CreateTransientSignedRepo()
+			var err error
+			verifier, err = signature.NewOpenPGPVerifier(signed.OpenPGPKeyRing)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("accepts a signed latest release", func(ctx context.Context) {
+			latest, err := LatestRelease(ctx, signed.Path, SemverTagMatcher, remote.WithVerifier(verifier))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest.Ref).To(Equal("refs/tags/v1.0.0"))
+			Expect(latest.IsAnnotated()).To(BeTrue())
+		})
+
+		It("rejects an unsigned latest release", func(ctx context.Context) {
+			Expect(LatestReleaseTag(ctx, signed.Path, NewPrefixedTagMatcher("unsigned-"),
+				remote.WithVerifier(verifier))).Error().To(MatchError(signature.ErrUnsigned))
+			Expect(LatestReleaseTag(ctx, signed.Path, NewPrefixedTagMatcher("unsigned-"))).
+				Error().NotTo(HaveOccurred())
+		})
+
+		It("rejects verifiers where it cannot verify", func(ctx context.Context) {
+			Expect(ListReleaseTags(ctx, signed.Path, SemverTagMatcher, remote.WithVerifier(verifier))).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+			Expect(ListReleaseTagsForScheme(ctx, signed.Path, SemverTagMatcher, remote.WithVerifier(verifier))).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+			Expect(RetractedVersions(ctx, signed.Path, RetractFilename, remote.WithVerifier(verifier))).
+				Error().To(MatchError(remote.ErrUnsupportedOption))
+		})
+
 	})
 
 })
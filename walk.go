@@ -0,0 +1,124 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitrepofs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// walkTreeFn is called by [FS.walkTree] for each tree entry. The name is the
+// slash-separated path of the entry relative to the root of the file system.
+type walkTreeFn func(name string, entry object.TreeEntry) error
+
+// subtree returns the tree for the named directory, which must have been
+// validated before using [fs.ValidPath].
+func (gfs *FS) subtree(dir string) (*object.Tree, error) {
+	if dir == "." {
+		return gfs.tree, nil
+	}
+	if gfs.exportIgnored(dir) {
+		return nil, fs.ErrNotExist
+	}
+	tree, err := gfs.tree.Tree(dir)
+	if err != nil {
+		return nil, fs.ErrNotExist
+	}
+	return tree, nil
+}
+
+// walkTree walks the tree of the named directory depth-first in git's tree
+// order, calling fn for each entry (but not for the directory itself). In
+// archive views, export-ignored entries are skipped.
+// When fn returns [fs.SkipDir] for a directory entry, walkTree skips the
+// contents of this directory.
+func (gfs *FS) walkTree(dir string, fn walkTreeFn) error {
+	tree, err := gfs.subtree(dir)
+	if err != nil {
+		return &fs.PathError{Op: "walk", Path: dir, Err: err}
+	}
+	return gfs.walkSubtree(dir, tree, fn)
+}
+
+// walkSubtree walks the specified tree of the named directory, see
+// [FS.walkTree]. As trees come from untrusted remotes, walkSubtree fails on
+// entry names that aren't single path elements, such as "..".
+func (gfs *FS) walkSubtree(dir string, tree *object.Tree, fn walkTreeFn) error {
+	entries := tree.Entries
+	if gfs.archive {
+		entries = gfs.exportedEntries(dir, tree)
+	}
+	for _, entry := range entries {
+		if !validEntryName(entry.Name) {
+			return &fs.PathError{Op: "walk", Path: dir + "/" + entry.Name, Err: fs.ErrInvalid}
+		}
+		name := path.Join(dir, entry.Name)
+		if err := fn(name, entry); err != nil {
+			if err == fs.SkipDir && entry.Mode == filemode.Dir {
+				continue
+			}
+			return err
+		}
+		if entry.Mode != filemode.Dir {
+			continue
+		}
+		subtree, err := gfs.repo.TreeObject(entry.Hash)
+		if err != nil {
+			return &fs.PathError{Op: "walk", Path: name, Err: fs.ErrNotExist}
+		}
+		if err := gfs.walkSubtree(name, subtree, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validEntryName returns true if the tree entry name is a single path
+// element, that is, neither empty, nor "." or "..", nor containing any path
+// separators.
+func validEntryName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsRune(name, '/') && !strings.ContainsRune(name, filepath.Separator)
+}
+
+// openBlob returns a reader for the contents of the named file as well as the
+// size of the contents, as read through this file system. For symbolic links,
+// the contents are the link target.
+func (gfs *FS) openBlob(name string, entry object.TreeEntry) (io.ReadCloser, int64, error) {
+	blob, err := gfs.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("missing blob for %q, reason: %w", name, err)
+	}
+	if entry.Mode == filemode.Symlink || !gfs.converts() {
+		r, err := blob.Reader()
+		if err != nil {
+			return nil, 0, err
+		}
+		return r, blob.Size, nil
+	}
+	contents, err := gfs.readConverted(name, blob)
+	if err != nil {
+		return nil, 0, err
+	}
+	return io.NopCloser(bytes.NewReader(contents)), int64(len(contents)), nil
+}